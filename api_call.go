@@ -20,13 +20,33 @@ type Response struct {
 	Metadata ChainResponseMetadata
 }
 
+// StreamResponse is the response of SendRequestStream, its body is read from the node while the caller reads it
+type StreamResponse struct {
+	// StatusCode is the HTTP status code of the response
+	StatusCode int
+	// Body is the response body, it must be closed by the caller
+	Body io.ReadCloser
+	// Headers is the response headers
+	Headers *http.Header
+	// Metadata is the response metadata, it includes trace of request which it takes to get the response
+	Metadata ChainResponseMetadata
+}
+
 // ApiCaller is the interface for making API calls
 type ApiCaller interface {
 	DoRequest(context context.Context, request *http.Request) (*Response, error)
 }
 
+// StreamApiCaller is an ApiCaller which can return the response body without reading it
+// ApiCallers which do not implement it are streamed from their buffered body
+type StreamApiCaller interface {
+	DoStreamRequest(context context.Context, request *http.Request) (*StreamResponse, error)
+}
+
 type apiCallerClient struct {
 	client *http.Client
+	// streamClient shares the transport of client without its total timeout, which would cut off reading long streams
+	streamClient *http.Client
 }
 
 func (a *apiCallerClient) DoRequest(ctx context.Context, request *http.Request) (*Response, error) {
@@ -34,12 +54,12 @@ func (a *apiCallerClient) DoRequest(ctx context.Context, request *http.Request)
 	if err != nil {
 		return nil, err
 	}
+	defer res.Body.Close()
 
-	resBody, err := io.ReadAll(res.Body)
+	resBody, err := io.ReadAll(limitBody(res.Body, maxBodySizeFrom(ctx)))
 	if err != nil {
 		return nil, err
 	}
-	defer res.Body.Close()
 
 	response := &Response{
 		StatusCode: res.StatusCode,
@@ -50,6 +70,19 @@ func (a *apiCallerClient) DoRequest(ctx context.Context, request *http.Request)
 	return response, nil
 }
 
+func (a *apiCallerClient) DoStreamRequest(ctx context.Context, request *http.Request) (*StreamResponse, error) {
+	res, err := a.streamClient.Do(request.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return &StreamResponse{
+		StatusCode: res.StatusCode,
+		Body:       res.Body,
+		Headers:    &res.Header,
+	}, nil
+}
+
 func createHttpClient() *http.Client {
 	transport := &http.Transport{
 		MaxIdleConns:        100,
@@ -64,3 +97,12 @@ func createHttpClient() *http.Client {
 
 	return client
 }
+
+// createStreamHttpClient returns a copy of client without total timeout
+// streams are bounded by the first byte timeout of the node and the context of the caller instead
+func createStreamHttpClient(client *http.Client) *http.Client {
+	streamClient := *client
+	streamClient.Timeout = 0
+
+	return &streamClient
+}
@@ -0,0 +1,62 @@
+package eznode
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// errBodyTooLarge means a response body is larger than MaxBodySize of the chain
+var errBodyTooLarge = errors.New("response body is too large")
+
+type maxBodySizeKey struct{}
+
+// withMaxBodySize tells the ApiCaller how large a response body can be, size 0 means unlimited
+func withMaxBodySize(ctx context.Context, size int64) context.Context {
+	if size <= 0 {
+		return ctx
+	}
+
+	return context.WithValue(ctx, maxBodySizeKey{}, size)
+}
+
+func maxBodySizeFrom(ctx context.Context) int64 {
+	size, _ := ctx.Value(maxBodySizeKey{}).(int64)
+	return size
+}
+
+// limitBody returns a reader which fails with errBodyTooLarge once more than size bytes are read, size 0 means unlimited
+func limitBody(body io.Reader, size int64) io.Reader {
+	if size <= 0 {
+		return body
+	}
+
+	return &limitedBody{
+		body:      body,
+		remaining: size,
+	}
+}
+
+type limitedBody struct {
+	body      io.Reader
+	remaining int64
+}
+
+func (l *limitedBody) Read(p []byte) (int, error) {
+	if l.remaining < 0 {
+		return 0, errBodyTooLarge
+	}
+
+	// read one byte more than remaining to tell a body of exactly size bytes from a larger one
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+
+	n, err := l.body.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		return n + int(l.remaining), errBodyTooLarge
+	}
+
+	return n, err
+}
@@ -0,0 +1,119 @@
+package eznode
+
+import (
+	"container/list"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Cache stores responses of cacheable requests, it must be safe for concurrent use
+type Cache interface {
+	// Get returns the response which is stored for key, if it is not expired
+	Get(key string) (*Response, bool)
+	// Set stores the response for key for ttl
+	Set(key string, response *Response, ttl time.Duration)
+}
+
+// LRUCache is an in-memory Cache which evicts least recently used responses when its byte budget is exceeded
+type LRUCache struct {
+	maxBytes int
+	bytes    int
+	entries  map[string]*list.Element
+	order    *list.List
+	mutex    *sync.Mutex
+}
+
+type lruCacheEntry struct {
+	key       string
+	response  *Response
+	size      int
+	expiresAt time.Time
+}
+
+// NewLRUCache creates new LRUCache which holds at most maxBytes of response bodies and headers
+func NewLRUCache(maxBytes int) *LRUCache {
+	return &LRUCache{
+		maxBytes: maxBytes,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+		mutex:    &sync.Mutex{},
+	}
+}
+
+func (c *LRUCache) Get(key string) (*Response, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := element.Value.(*lruCacheEntry)
+	if !time.Now().Before(entry.expiresAt) {
+		c.removeElement(element)
+		return nil, false
+	}
+
+	c.order.MoveToFront(element)
+	return copyResponse(entry.response), true
+}
+
+func (c *LRUCache) Set(key string, response *Response, ttl time.Duration) {
+	size := len(key) + len(response.Body)
+	if response.Headers != nil {
+		for name, values := range *response.Headers {
+			size += len(name)
+			for _, value := range values {
+				size += len(value)
+			}
+		}
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		c.removeElement(element)
+	}
+
+	if size > c.maxBytes || ttl <= 0 {
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&lruCacheEntry{
+		key:       key,
+		response:  copyResponse(response),
+		size:      size,
+		expiresAt: time.Now().Add(ttl),
+	})
+	c.bytes += size
+
+	for c.bytes > c.maxBytes {
+		c.removeElement(c.order.Back())
+	}
+}
+
+func (c *LRUCache) removeElement(element *list.Element) {
+	entry := c.order.Remove(element).(*lruCacheEntry)
+	delete(c.entries, entry.key)
+	c.bytes -= entry.size
+}
+
+// copyResponse copies status code, body and headers of the response, metadata is not copied
+func copyResponse(response *Response) *Response {
+	copied := &Response{
+		StatusCode: response.StatusCode,
+		Body:       append([]byte(nil), response.Body...),
+	}
+
+	if response.Headers != nil {
+		headers := response.Headers.Clone()
+		copied.Headers = &headers
+	} else {
+		copied.Headers = &http.Header{}
+	}
+
+	return copied
+}
@@ -0,0 +1,163 @@
+package eznode
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRUCache(t *testing.T) {
+	t.Parallel()
+
+	cache := NewLRUCache(20)
+	cache.Set("a", &Response{StatusCode: http.StatusOK, Body: []byte("123456789")}, time.Minute)
+	cache.Set("b", &Response{StatusCode: http.StatusOK, Body: []byte("123456789")}, time.Minute)
+
+	res, ok := cache.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, "123456789", string(res.Body))
+	res.Body[0] = 'x'
+
+	cache.Set("c", &Response{StatusCode: http.StatusOK, Body: []byte("123456789")}, time.Minute)
+	_, ok = cache.Get("b")
+	assert.False(t, ok, "least recently used response should be evicted")
+
+	res, ok = cache.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, "123456789", string(res.Body), "cached response should not be changed by callers")
+
+	cache.Set("d", &Response{StatusCode: http.StatusOK, Body: []byte("123456789")}, time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	_, ok = cache.Get("d")
+	assert.False(t, ok, "expired response should not be returned")
+
+	cache.Set("e", &Response{StatusCode: http.StatusOK, Body: make([]byte, 100)}, time.Minute)
+	_, ok = cache.Get("e")
+	assert.False(t, ok, "response larger than the budget should not be cached")
+}
+
+func TestResponseCache(t *testing.T) {
+	t.Parallel()
+
+	var upstreamCalls int64
+	mockedApiCall := contextApiCall(func(ctx context.Context, request *http.Request) (*Response, error) {
+		body, _ := io.ReadAll(request.Body)
+		call, _ := parseJsonRpcCall(body)
+
+		var result any
+		switch call.Method {
+		case "eth_blockNumber":
+			result = "0x64"
+		case "eth_getTransactionReceipt":
+			atomic.AddInt64(&upstreamCalls, 1)
+			result = nil
+		default:
+			atomic.AddInt64(&upstreamCalls, 1)
+			result = map[string]string{"number": "block"}
+		}
+		responseBody, _ := json.Marshal(map[string]any{"jsonrpc": "2.0", "id": call.Id, "result": result})
+
+		return &Response{
+			StatusCode: http.StatusOK,
+			Body:       responseBody,
+			Headers:    &http.Header{},
+		}, nil
+	})
+
+	chainNode1 := NewChainNode(NewChainNodeConfig{
+		Name: "Node 1",
+		Url:  "http://example.com",
+		Limit: ChainNodeLimit{
+			Count: 100,
+			Per:   1 * time.Minute,
+		},
+		RequestTimeout: 1 * time.Second,
+		Priority:       1,
+	})
+
+	createdChain := NewChain(
+		NewChainConfig{
+			Id: "test-chain",
+			Nodes: []*ChainNode{
+				chainNode1,
+			},
+			CheckTickRate: CheckTick{
+				TickRate:         100 * time.Millisecond,
+				MaxCheckDuration: 200 * time.Millisecond,
+			},
+			HeadTracker: &HeadTrackerConfig{
+				Interval:  time.Minute,
+				MaxLag:    5,
+				Extractor: EthereumHeadExtractor{},
+			},
+			CachePolicy: &CachePolicy{
+				Rules: []CacheRule{
+					{
+						Methods:          []string{"eth_getBlockByNumber"},
+						TTL:              time.Minute,
+						MinConfirmations: 10,
+						BlockNumber:      JsonRpcBlockParam(0),
+					},
+					{
+						Methods: []string{"eth_getTransactionReceipt"},
+						TTL:     time.Minute,
+					},
+				},
+			},
+		},
+	)
+
+	ezNode := NewEzNode([]*Chain{createdChain}, WithApiClient(mockedApiCall), WithCache(NewLRUCache(1<<20)))
+	defer ezNode.Stop()
+	assert.Eventually(t, func() bool {
+		createdChain.mutex.RLock()
+		defer createdChain.mutex.RUnlock()
+		return createdChain.bestHead == 100
+	}, time.Second, 10*time.Millisecond)
+
+	call := func(id int, method string, params string) *Response {
+		body := `{"jsonrpc":"2.0","id":` + strconv.Itoa(id) + `,"method":"` + method + `","params":` + params + `}`
+		request, _ := http.NewRequest("POST", "", bytes.NewBufferString(body))
+		res, err := ezNode.SendRequest(context.Background(), "test-chain", request)
+		assert.Nil(t, err)
+		return res
+	}
+
+	res := call(1, "eth_getBlockByNumber", `["0x10", false]`)
+	assert.False(t, res.Metadata.Cached)
+	res = call(2, "eth_getBlockByNumber", `["0x10",false]`)
+	assert.True(t, res.Metadata.Cached)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","id":2,"result":{"number":"block"}}`, string(res.Body))
+	assert.Equal(t, int64(1), atomic.LoadInt64(&upstreamCalls))
+
+	call(1, "eth_getBlockByNumber", `["0x60", false]`)
+	call(1, "eth_getBlockByNumber", `["0x60", false]`)
+	call(1, "eth_getBlockByNumber", `["latest", false]`)
+	call(1, "eth_getBlockByNumber", `["latest", false]`)
+	assert.Equal(t, int64(5), atomic.LoadInt64(&upstreamCalls), "recent blocks should not be cached")
+
+	call(1, "eth_getTransactionReceipt", `["0xabc"]`)
+	res = call(1, "eth_getTransactionReceipt", `["0xabc"]`)
+	assert.False(t, res.Metadata.Cached, "null result should not be cached")
+
+	stats := ezNode.GetStats()
+	assert.Equal(t, uint64(1), stats[0].CacheHits)
+	assert.Equal(t, uint64(3), stats[0].CacheMisses)
+
+	callSpecific := func() *Response {
+		request, _ := http.NewRequest("POST", "", bytes.NewBufferString(`{"jsonrpc":"2.0","id":1,"method":"eth_getBlockByNumber","params":["0x10",false]}`))
+		res, err := ezNode.SendRequestSpecific(context.Background(), "test-chain", request, []string{"Node 1"})
+		assert.Nil(t, err)
+		return res
+	}
+	assert.False(t, callSpecific().Metadata.Cached, "response of any node should not be shared with a request pinned to a node")
+	assert.True(t, callSpecific().Metadata.Cached)
+}
@@ -1,9 +1,14 @@
 package eznode
 
 import (
+	"context"
+	"errors"
 	"log"
 	"net/http"
+	"path"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -17,6 +22,26 @@ type Chain struct {
 	checkTickRate      CheckTick
 	failureStatusCodes map[int]bool
 	retryCount         int
+	strictRetryCount   bool
+	retryPolicy        *RetryPolicy
+	retryBudget        *retryBudget
+	selector           Selector
+	costFunc           CostFunc
+	maxRetryAfter      time.Duration
+	healthCheck        *HealthCheckConfig
+	headTracker        *HeadTrackerConfig
+	bestHead           uint64
+	backgroundMutex    *sync.Mutex
+	done               chan struct{}
+	waitQueue          *waitQueue
+	maxQueueLength     int
+	backgroundCapacity float64
+	responseValidator  ResponseValidator
+	routes             []Route
+	cachePolicy        *CachePolicy
+	cacheHits          uint64
+	cacheMisses        uint64
+	maxBodySize        int64
 }
 
 type NewChainConfig struct {
@@ -28,40 +53,212 @@ type NewChainConfig struct {
 	CheckTickRate CheckTick
 	// list of http status codes which recognized as failure
 	FailureStatusCodes []int
-	// number of retries for failed requests
+	// number of retries for failed requests, 0 means a failed request is not retried
+	// a failed request is retried on every other node which can serve it, unless StrictRetryCount is set
 	RetryCount int
+	// StrictRetryCount makes RetryCount the max number of retries, so a request is sent at most RetryCount+1 times
+	// StrictRetryCount is optional
+	StrictRetryCount bool
+	// RetryPolicy determines how long to wait between attempts of a request
+	// RetryPolicy is optional, next node is tried immediately by default
+	RetryPolicy *RetryPolicy
+	// RetryBudget caps retries of all requests of the chain, requests fail fast when it is exhausted
+	// RetryBudget is optional
+	RetryBudget *RetryBudgetConfig
+	// Selector picks which node serves a request among the free nodes of the highest priority
+	// Selector is optional, LeastHitsSelector is used by default
+	Selector Selector
+	// HealthCheck probes nodes in background and takes unhealthy nodes out of the chain
+	// HealthCheck is optional
+	HealthCheck *HealthCheckConfig
+	// HeadTracker polls the latest block of nodes and takes stale nodes out of the chain
+	// HeadTracker is optional
+	HeadTracker *HeadTrackerConfig
+	// CostFunc determines how much of the node limits a request consumes
+	// CostFunc is optional, every request costs 1 by default
+	CostFunc CostFunc
+	// MaxRetryAfter caps how long a node is throttled when it responds 429 or 503 with Retry-After header
+	// MaxRetryAfter is optional, Retry-After is honored as is by default
+	MaxRetryAfter time.Duration
+	// MaxQueueLength is max number of requests which wait for a free node
+	// requests fail immediately when the queue is full (load shedding)
+	// MaxQueueLength is optional, the queue is unlimited by default
+	MaxQueueLength int
+	// BackgroundCapacity is the fraction of each node limit which PriorityBackground requests can use, e.g. 0.3
+	// background requests can always use at least one request of each limit
+	// BackgroundCapacity is optional, background requests can use the whole limit by default
+	BackgroundCapacity float64
+	// ResponseValidator checks bodies of responses which have a successful status code, e.g. JSON-RPC errors
+	// ResponseValidator is optional, only status code of responses is checked by default
+	ResponseValidator ResponseValidator
+	// Routes send requests which match them only to nodes which have their tags, e.g. trace methods to archive nodes
+	// Routes is optional
+	Routes []Route
+	// CachePolicy determines which responses are cached, e.g. blocks which are older than N blocks
+	// CachePolicy is optional, it is used only when EzNode has a Cache
+	CachePolicy *CachePolicy
+	// MaxBodySize is max number of bytes of a response body, larger responses are rejected
+	// MaxBodySize is optional, bodies are not limited by default
+	MaxBodySize int64
 }
 
-// NewChain creates new Chain
-// If FailureStatusCodes is not specified, default list of status codes is used
-func NewChain(
-	chainData NewChainConfig,
-) *Chain {
-	if chainData.Id == "" {
-		log.Fatal("id cannot be empty")
+// Validate reports every invalid field of the config
+func (c NewChainConfig) Validate() error {
+	errs := fieldErrors{}
+	if c.Id == "" {
+		errs.add("id", "cannot be empty")
+	}
+
+	if c.CheckTickRate.TickRate < 50*time.Millisecond {
+		errs.add("checkTickRate.tickRate", "cannot be less than 50 millisecond")
+	}
+
+	if c.CheckTickRate.MaxCheckDuration < c.CheckTickRate.TickRate {
+		errs.add("checkTickRate.maxCheckDuration", "must be greater than tick rate")
 	}
 
-	if chainData.CheckTickRate.TickRate < 50*time.Millisecond {
-		log.Fatal("tick rate cannot be less than 50 millisecond")
+	if c.RetryCount < 0 {
+		errs.add("retryCount", "must be greater than -1")
 	}
 
-	if chainData.CheckTickRate.MaxCheckDuration < chainData.CheckTickRate.TickRate {
-		log.Fatal("max check duration must be greater than tick rate")
+	if c.MaxQueueLength < 0 {
+		errs.add("maxQueueLength", "cannot be less than 0")
+	}
+
+	if c.MaxBodySize < 0 {
+		errs.add("maxBodySize", "cannot be less than 0")
+	}
+
+	if c.BackgroundCapacity < 0 || c.BackgroundCapacity > 1 {
+		errs.add("backgroundCapacity", "must be between 0 and 1")
+	}
+
+	if c.RetryBudget != nil {
+		if c.RetryBudget.Ratio < 0 {
+			errs.add("retryBudget.ratio", "cannot be less than 0")
+		}
+
+		if c.RetryBudget.Window < retryBudgetBuckets {
+			errs.add("retryBudget.window", "is too short")
+		}
+	}
+
+	if c.RetryPolicy != nil {
+		if c.RetryPolicy.InitialDelay < 0 {
+			errs.add("retryPolicy.initialDelay", "cannot be less than 0")
+		}
+
+		if c.RetryPolicy.Multiplier != 0 && c.RetryPolicy.Multiplier < 1 {
+			errs.add("retryPolicy.multiplier", "cannot be less than 1")
+		}
+	}
+
+	if c.HealthCheck != nil && c.HealthCheck.Interval < 1 {
+		errs.add("healthCheck.interval", "cannot be less than 1")
+	}
+
+	if c.HeadTracker != nil {
+		if c.HeadTracker.Interval < 1 {
+			errs.add("headTracker.interval", "cannot be less than 1")
+		}
+
+		if c.HeadTracker.Extractor == nil {
+			errs.add("headTracker.extractor", "cannot be empty")
+		}
+	}
+
+	for i, route := range c.Routes {
+		field := indexedField("routes", i)
+		if len(route.Methods) == 0 && route.PathPattern == "" {
+			errs.add(field, "methods and pathPattern cannot be both empty")
+		}
+
+		if len(route.Tags) == 0 {
+			errs.add(field+".tags", "cannot be empty")
+		}
+
+		if _, err := path.Match(route.PathPattern, ""); err != nil {
+			errs.add(field+".pathPattern", "is malformed")
+		}
+
+		routeTags := make(map[string]bool)
+		for _, tag := range route.Tags {
+			routeTags[tag] = true
+		}
+		if len(routeTags) > 0 && !slices.ContainsFunc(c.Nodes, func(node *ChainNode) bool {
+			return node != nil && node.hasTags(routeTags)
+		}) {
+			errs.add(field+".tags", "no node has all of the tags")
+		}
 	}
 
-	if chainData.RetryCount < 0 {
-		log.Fatal("retry must be greater than -1")
+	if c.CachePolicy != nil {
+		for i, rule := range c.CachePolicy.Rules {
+			field := indexedField("cachePolicy.rules", i)
+			if len(rule.Methods) == 0 && rule.PathPattern == "" {
+				errs.add(field, "methods and pathPattern cannot be both empty")
+			}
+
+			if rule.TTL < 1 {
+				errs.add(field+".ttl", "cannot be less than 1")
+			}
+
+			if _, err := path.Match(rule.PathPattern, ""); err != nil {
+				errs.add(field+".pathPattern", "is malformed")
+			}
+
+			if rule.MinConfirmations > 0 && (rule.BlockNumber == nil || c.HeadTracker == nil) {
+				errs.add(field+".minConfirmations", "needs blockNumber and headTracker")
+			}
+		}
 	}
 
 	seenName := make(map[string]bool)
-	for _, node := range chainData.Nodes {
+	for i, node := range c.Nodes {
+		if node == nil {
+			errs.add(indexedField("nodes", i), "cannot be nil")
+			continue
+		}
+
 		if seenName[node.name] {
-			log.Fatal("you cannot have duplicate name")
+			errs.add(indexedField("nodes", i)+".name", "cannot be duplicate")
 		}
 
 		seenName[node.name] = true
 	}
 
+	return errs.err()
+}
+
+// NewChain creates new Chain
+// If FailureStatusCodes is not specified, default list of status codes is used
+// If Selector is not specified, LeastHitsSelector is used
+// it calls log.Fatal if the config is invalid, use NewChainE to handle the error
+func NewChain(
+	chainData NewChainConfig,
+) *Chain {
+	chain, err := NewChainE(chainData)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return chain
+}
+
+// NewChainE creates new Chain like NewChain
+// it returns *ValidationError if the config is invalid
+func NewChainE(
+	chainData NewChainConfig,
+) (*Chain, error) {
+	if err := chainData.Validate(); err != nil {
+		return nil, err
+	}
+
+	var budget *retryBudget
+	if chainData.RetryBudget != nil {
+		budget = newRetryBudget(*chainData.RetryBudget)
+	}
+
 	failureStatusCodes := make(map[int]bool)
 	if chainData.FailureStatusCodes != nil {
 		for _, statusCode := range chainData.FailureStatusCodes {
@@ -73,65 +270,246 @@ func NewChain(
 		}
 	}
 
+	var healthCheck *HealthCheckConfig
+	if chainData.HealthCheck != nil {
+		config := *chainData.HealthCheck
+		if config.Method == "" {
+			config.Method = http.MethodGet
+		}
+		if config.ExpectedStatusCode == 0 {
+			config.ExpectedStatusCode = http.StatusOK
+		}
+		if config.FailureThreshold == 0 {
+			config.FailureThreshold = 1
+		}
+		if config.SuccessThreshold == 0 {
+			config.SuccessThreshold = 1
+		}
+		healthCheck = &config
+	}
+
+	selector := chainData.Selector
+	if selector == nil {
+		selector = NewLeastHitsSelector()
+	}
+
 	return &Chain{
 		id:                 chainData.Id,
 		mutex:              &sync.RWMutex{},
 		checkTickRate:      chainData.CheckTickRate,
 		failureStatusCodes: failureStatusCodes,
 		retryCount:         chainData.RetryCount,
+		strictRetryCount:   chainData.StrictRetryCount,
+		retryPolicy:        chainData.RetryPolicy,
+		retryBudget:        budget,
 		nodes:              chainData.Nodes,
+		selector:           selector,
+		costFunc:           chainData.CostFunc,
+		maxRetryAfter:      chainData.MaxRetryAfter,
+		healthCheck:        healthCheck,
+		headTracker:        chainData.HeadTracker,
+		backgroundMutex:    &sync.Mutex{},
+		waitQueue:          &waitQueue{},
+		maxQueueLength:     chainData.MaxQueueLength,
+		backgroundCapacity: chainData.BackgroundCapacity,
+		responseValidator:  chainData.ResponseValidator,
+		routes:             chainData.Routes,
+		cachePolicy:        chainData.CachePolicy,
+		maxBodySize:        chainData.MaxBodySize,
+	}, nil
+}
+
+// nodeQuery determines which nodes can serve a request
+type nodeQuery struct {
+	excludeNodes map[string]bool
+	includeNodes map[string]bool
+	cost         uint
+	maxWait      time.Duration
+	class        PriorityClass
+	requiredTags map[string]bool
+}
+
+func (q nodeQuery) requestCost() float64 {
+	if q.cost < 1 {
+		return 1
 	}
+
+	return float64(q.cost)
 }
 
-func (c *Chain) getFreeNode(excludeNodes map[string]bool, includeNodes map[string]bool) *ChainNode {
-	if len(excludeNodes) == len(c.nodes) {
-		return nil
+// maxRetries returns how many times a failed request is retried
+// without StrictRetryCount, every other node which can serve the request is tried once RetryCount is greater than 0
+func (c *Chain) maxRetries(includeNodes map[string]bool, requiredTags map[string]bool) int {
+	if c.strictRetryCount || c.retryCount == 0 {
+		return c.retryCount
 	}
 
-	firstLoadNode := c.findNode(excludeNodes, includeNodes)
-	if firstLoadNode != nil {
-		return firstLoadNode
+	servingNodes := 0
+	for _, node := range c.nodes {
+		if (len(includeNodes) == 0 || includeNodes[node.name]) && node.hasTags(requiredTags) {
+			servingNodes += 1
+		}
 	}
 
-	deadlineToFind := time.After(c.checkTickRate.MaxCheckDuration)
-	ticker := time.NewTicker(c.checkTickRate.TickRate)
+	return max(servingNodes-1, 0)
+}
 
-	for {
-		select {
-		case <-deadlineToFind:
-			return nil
-		case <-ticker.C:
-			foundNode := c.findNode(excludeNodes, includeNodes)
-			if foundNode != nil {
-				return foundNode
-			}
+// reserveFor returns the fraction of node limits which requests of the class cannot use
+func (c *Chain) reserveFor(class PriorityClass) float64 {
+	if class == PriorityBackground && c.backgroundCapacity > 0 {
+		return 1 - c.backgroundCapacity
+	}
+
+	return 0
+}
+
+func (c *Chain) requestCost(requestOpts *requestOptions, request *http.Request, body []byte) uint {
+	if requestOpts.cost > 0 {
+		return requestOpts.cost
+	}
+
+	if c.costFunc != nil {
+		return c.costFunc(request, body)
+	}
+
+	return 1
+}
+
+var (
+	// errNoFreeNode means no node became free within max wait duration
+	errNoFreeNode = errors.New("no free node")
+	// errQueueFull means the wait queue of the chain reached its max length
+	errQueueFull = errors.New("wait queue is full")
+	// errNoMatchingNode means no node of the chain has the tags which the query requires
+	errNoMatchingNode = errors.New("no node has the required tags")
+)
+
+// getFreeNode waits in the chain wait queue until a node is free to serve the query
+// waiters are served by priority class and then arrival order, it stops waiting when ctx is done or max wait duration passes
+func (c *Chain) getFreeNode(ctx context.Context, query nodeQuery) (*ChainNode, error) {
+	if len(query.excludeNodes) == len(c.nodes) {
+		return nil, errNoFreeNode
+	}
+
+	// tags of nodes never change, so waiting cannot help a query which no node can serve
+	if !c.hasTaggedNode(query.requiredTags) {
+		return nil, errNoMatchingNode
+	}
+
+	w := &waiter{
+		query: query,
+		node:  make(chan *ChainNode, 1),
+	}
+
+	c.mutex.Lock()
+	c.waitQueue.add(w)
+	c.dispatchLocked()
+	if len(w.node) == 0 && c.maxQueueLength > 0 && len(c.waitQueue.waiters) > c.maxQueueLength {
+		c.waitQueue.remove(w)
+		c.mutex.Unlock()
+		return nil, errQueueFull
+	}
+	c.mutex.Unlock()
+
+	maxWait := c.checkTickRate.MaxCheckDuration
+	if query.maxWait > 0 {
+		maxWait = query.maxWait
+	}
+
+	deadlineToFind := time.NewTimer(maxWait)
+	defer deadlineToFind.Stop()
+
+	var err error
+	select {
+	case foundNode := <-w.node:
+		return foundNode, nil
+	case <-ctx.Done():
+		err = ctx.Err()
+	case <-deadlineToFind.C:
+		err = errNoFreeNode
+	}
+
+	c.mutex.Lock()
+	removed := c.waitQueue.remove(w)
+	c.mutex.Unlock()
+	if !removed {
+		// the node was handed over while giving up, it is already taken for this request
+		return <-w.node, nil
+	}
+
+	return nil, err
+}
+
+// hasTaggedNode reports whether any node of the chain has all of the tags, regardless of whether it is free
+func (c *Chain) hasTaggedNode(tags map[string]bool) bool {
+	for _, node := range c.nodes {
+		if node.hasTags(tags) {
+			return true
 		}
 	}
+
+	return false
 }
 
-func (c *Chain) findNode(excludeNodes map[string]bool, includeNodes map[string]bool) *ChainNode {
+// findNode takes a free node for the query without waiting
+// it returns nil while requests wait in the queue, so it does not take capacity ahead of them
+func (c *Chain) findNode(query nodeQuery) *ChainNode {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
-	selectedNode := &ChainNode{
-		priority: -1,
+	if len(c.waitQueue.waiters) > 0 {
+		return nil
 	}
 
+	return c.findNodeLocked(query, time.Now(), nil)
+}
+
+// canServeLocked reports whether the node can serve the query apart from its limits, it must be called while the chain is locked
+func (c *Chain) canServeLocked(node *ChainNode, query nodeQuery, now time.Time) bool {
+	return !query.excludeNodes[node.name] &&
+		(len(query.includeNodes) == 0 || query.includeNodes[node.name]) &&
+		node.hasTags(query.requiredTags) &&
+		node.isAvailable(now) &&
+		(c.headTracker == nil || c.headLag(node) <= c.headTracker.MaxLag)
+}
+
+// findNodeLocked selects a node and takes it for the query, it must be called while the chain is locked
+// held nodes are kept for other waiters and are not selected
+func (c *Chain) findNodeLocked(query nodeQuery, now time.Time, held map[*ChainNode]bool) *ChainNode {
+	cost := query.requestCost()
+	reserve := c.reserveFor(query.class)
+	candidates := make([]*ChainNode, 0, len(c.nodes))
 	for _, node := range c.nodes {
-		if !excludeNodes[node.name] &&
-			((len(includeNodes) > 0 && includeNodes[node.name]) || len(includeNodes) == 0) &&
-			node.priority >= selectedNode.priority &&
-			node.hits < node.limit.Count &&
-			(node.hits <= selectedNode.hits || selectedNode.priority == -1) &&
-			!node.disabled {
-			selectedNode = node
+		if held[node] || !node.allowRequest(now, cost, reserve) || !c.canServeLocked(node, query, now) {
+			continue
+		}
+
+		if len(candidates) > 0 {
+			if node.priority < candidates[0].priority {
+				continue
+			}
+
+			if node.priority > candidates[0].priority {
+				candidates = candidates[:0]
+			}
 		}
+
+		candidates = append(candidates, node)
 	}
 
-	if selectedNode.priority == -1 {
+	if len(candidates) == 0 {
 		return nil
 	}
 
-	selectedNode.hits += 1
+	selectedNode := c.selector.Select(candidates)
+	if selectedNode == nil {
+		return nil
+	}
+
+	selectedNode.takeRequest(now, cost)
+	if selectedNode.breaker != nil {
+		selectedNode.breaker.onSelected()
+	}
+	atomic.AddInt64(&selectedNode.inFlight, 1)
 	return selectedNode
 }
@@ -0,0 +1,33 @@
+package eznode
+
+// start runs background jobs of the chain, it is safe to call more than once
+func (c *Chain) start(apiCaller ApiCaller) {
+	c.backgroundMutex.Lock()
+	defer c.backgroundMutex.Unlock()
+
+	if c.done != nil {
+		return
+	}
+	c.done = make(chan struct{})
+
+	if c.healthCheck != nil {
+		go c.runHealthCheck(apiCaller, c.done)
+	}
+
+	if c.headTracker != nil {
+		go c.runHeadTracker(apiCaller, c.done)
+	}
+}
+
+// stop stops background jobs of the chain
+func (c *Chain) stop() {
+	c.backgroundMutex.Lock()
+	defer c.backgroundMutex.Unlock()
+
+	if c.done == nil {
+		return
+	}
+
+	close(c.done)
+	c.done = nil
+}
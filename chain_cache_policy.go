@@ -0,0 +1,127 @@
+package eznode
+
+import (
+	"encoding/json"
+	"net/http"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CachePolicy determines which requests of the chain are cached and for how long
+// requests are cached only if EzNode has a Cache, see WithCache
+type CachePolicy struct {
+	// Rules are checked in order, the first rule which matches a request is used
+	Rules []CacheRule
+}
+
+// CacheRule caches responses of requests which match it
+type CacheRule struct {
+	// Methods are JSON-RPC methods which match the rule, e.g. eth_getTransactionReceipt
+	Methods []string
+	// PathPattern is a path.Match pattern which matches url path of requests, e.g. /blocks/*
+	PathPattern string
+	// TTL is how long a response is cached
+	TTL time.Duration
+	// MinConfirmations caches a response only if its block is at least MinConfirmations behind the best known head
+	// it needs HeadTracker on the chain and BlockNumber on the rule
+	// MinConfirmations is optional
+	MinConfirmations uint64
+	// BlockNumber returns the block which the request asks about, it returns false if the request has no block number
+	// JsonRpcBlockParam is available
+	// BlockNumber is optional
+	BlockNumber func(request *http.Request, body []byte) (uint64, bool)
+}
+
+// JsonRpcBlockParam returns a BlockNumber function which reads the block number from params of a JSON-RPC request
+// index is the position of the hex block number in params, e.g. 0 for eth_getBlockByNumber
+func JsonRpcBlockParam(index int) func(request *http.Request, body []byte) (uint64, bool) {
+	return func(request *http.Request, body []byte) (uint64, bool) {
+		call, ok := parseJsonRpcCall(body)
+		if !ok {
+			return 0, false
+		}
+
+		var params []json.RawMessage
+		if err := json.Unmarshal(call.Params, &params); err != nil || index >= len(params) {
+			return 0, false
+		}
+
+		var blockNumber string
+		if err := json.Unmarshal(params[index], &blockNumber); err != nil || !strings.HasPrefix(blockNumber, "0x") {
+			return 0, false
+		}
+
+		number, err := strconv.ParseUint(blockNumber[2:], 16, 64)
+		if err != nil {
+			return 0, false
+		}
+
+		return number, true
+	}
+}
+
+func (r CacheRule) matches(request *http.Request, body []byte) bool {
+	if call, ok := parseJsonRpcCall(body); ok && slices.Contains(r.Methods, call.Method) {
+		return true
+	}
+
+	if r.PathPattern == "" {
+		return false
+	}
+
+	matched, _ := path.Match(r.PathPattern, request.URL.Path)
+	return matched
+}
+
+// cacheTTL returns how long the response of the request can be cached, it returns false if it cannot be cached
+func (c *Chain) cacheTTL(request *http.Request, body []byte) (time.Duration, bool) {
+	if c.cachePolicy == nil {
+		return 0, false
+	}
+
+	for _, rule := range c.cachePolicy.Rules {
+		if !rule.matches(request, body) {
+			continue
+		}
+
+		if rule.MinConfirmations > 0 {
+			blockNumber, ok := rule.BlockNumber(request, body)
+			if !ok {
+				return 0, false
+			}
+
+			c.mutex.RLock()
+			bestHead := c.bestHead
+			c.mutex.RUnlock()
+
+			if bestHead < blockNumber || bestHead-blockNumber < rule.MinConfirmations {
+				return 0, false
+			}
+		}
+
+		return rule.TTL, true
+	}
+
+	return 0, false
+}
+
+// isResponseCacheable reports whether the response has an answer which does not change
+// JSON-RPC errors and null results, e.g. a receipt of a pending transaction, are not cached
+func isResponseCacheable(res *Response) bool {
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return false
+	}
+
+	var rpcResponse struct {
+		jsonRpcResponse
+		JsonRpc string `json:"jsonrpc"`
+	}
+	if err := json.Unmarshal(res.Body, &rpcResponse); err != nil || rpcResponse.JsonRpc == "" {
+		return true
+	}
+
+	return rpcResponse.Error == nil && len(rpcResponse.Result) > 0 && string(rpcResponse.Result) != "null"
+}
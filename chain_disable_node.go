@@ -29,6 +29,11 @@ func (c *Chain) enableNode(nodeName string) {
 	for _, node := range c.nodes {
 		if node.name == nodeName {
 			node.disabled = false
+			if node.breaker != nil {
+				node.breaker.reset()
+			}
 		}
 	}
+
+	c.dispatchLocked()
 }
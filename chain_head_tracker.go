@@ -0,0 +1,98 @@
+package eznode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// HeadTrackerConfig determines how the latest block of chain nodes is tracked
+// nodes which are more than MaxLag blocks behind the best known head are taken out of the chain
+type HeadTrackerConfig struct {
+	// Interval is the time between two polls of the nodes
+	Interval time.Duration
+	// Timeout of the poll request
+	// Timeout is optional, RequestTimeout of the node is used by default
+	Timeout time.Duration
+	// MaxLag is the max number of blocks a node can be behind the best known head
+	MaxLag uint64
+	// Extractor asks a node about its latest block
+	// EthereumHeadExtractor, BitcoinHeadExtractor and CosmosHeadExtractor are available
+	Extractor HeadExtractor
+}
+
+func (c *Chain) runHeadTracker(apiCaller ApiCaller, done <-chan struct{}) {
+	ticker := time.NewTicker(c.headTracker.Interval)
+	defer ticker.Stop()
+
+	for {
+		c.pollNodesHead(apiCaller)
+
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *Chain) pollNodesHead(apiCaller ApiCaller) {
+	wg := &sync.WaitGroup{}
+	for _, node := range c.nodes {
+		wg.Add(1)
+		go func(node *ChainNode) {
+			defer wg.Done()
+
+			head, err := c.fetchNodeHead(apiCaller, node)
+			if err == nil {
+				c.reportHead(node, head)
+			}
+		}(node)
+	}
+	wg.Wait()
+}
+
+func (c *Chain) fetchNodeHead(apiCaller ApiCaller, node *ChainNode) (uint64, error) {
+	request, err := c.headTracker.Extractor.Request()
+	if err != nil {
+		return 0, err
+	}
+
+	res, err := probeNode(context.Background(), apiCaller, node, request, c.headTracker.Timeout)
+	if err != nil {
+		return 0, err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return 0, errors.New(fmt.Sprintf("head request failed with status code %v", res.StatusCode))
+	}
+
+	return c.headTracker.Extractor.Extract(res)
+}
+
+func (c *Chain) reportHead(node *ChainNode, head uint64) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	node.head = head
+
+	bestHead := uint64(0)
+	for _, chainNode := range c.nodes {
+		if chainNode.head > bestHead {
+			bestHead = chainNode.head
+		}
+	}
+	c.bestHead = bestHead
+}
+
+// headLag returns how many blocks the node is behind the best known head, it must be called while the chain is locked
+func (c *Chain) headLag(node *ChainNode) uint64 {
+	if c.headTracker == nil || node.head >= c.bestHead {
+		return 0
+	}
+
+	return c.bestHead - node.head
+}
@@ -0,0 +1,110 @@
+package eznode
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeadTrackerExcludesStaleNode(t *testing.T) {
+	t.Parallel()
+
+	mockedApiCall := mockApiCall{
+		returnFunc: func(request *http.Request) (*Response, error) {
+			head := `"0x64"`
+			if request.URL.Host == "example2.com" {
+				head = `"0x5a"`
+			}
+
+			return &Response{
+				StatusCode: http.StatusOK,
+				Body:       []byte(`{"jsonrpc":"2.0","id":1,"result":` + head + `}`),
+				Headers:    &http.Header{},
+			}, nil
+		},
+		validateFunc: func(request *http.Request) {
+			body, _ := io.ReadAll(request.Body)
+			assert.Contains(t, string(body), "eth_blockNumber")
+		},
+	}
+
+	chainNode1 := NewChainNode(NewChainNodeConfig{
+		Name: "Node 1",
+		Url:  "http://example.com",
+		Limit: ChainNodeLimit{
+			Count: 10,
+			Per:   1 * time.Second,
+		},
+		RequestTimeout: 1 * time.Second,
+		Priority:       1,
+	})
+
+	chainNode2 := NewChainNode(NewChainNodeConfig{
+		Name: "Node 2",
+		Url:  "http://example2.com",
+		Limit: ChainNodeLimit{
+			Count: 10,
+			Per:   1 * time.Second,
+		},
+		RequestTimeout: 1 * time.Second,
+		Priority:       2,
+	})
+
+	createdChain := NewChain(
+		NewChainConfig{
+			Id: "test-chain",
+			Nodes: []*ChainNode{
+				chainNode1,
+				chainNode2,
+			},
+			CheckTickRate: CheckTick{
+				TickRate:         100 * time.Millisecond,
+				MaxCheckDuration: 200 * time.Millisecond,
+			},
+			RetryCount: 2,
+			HeadTracker: &HeadTrackerConfig{
+				Interval:  time.Minute,
+				MaxLag:    5,
+				Extractor: EthereumHeadExtractor{},
+			},
+		},
+	)
+
+	ezNode := NewEzNode([]*Chain{createdChain}, WithApiClient(mockedApiCall))
+	defer ezNode.Stop()
+
+	time.Sleep(50 * time.Millisecond)
+	stats := ezNode.GetStats()[0].Nodes
+	assert.Equal(t, uint64(100), stats[0].Head)
+	assert.Equal(t, uint64(0), stats[0].HeadLag)
+	assert.Equal(t, uint64(90), stats[1].Head)
+	assert.Equal(t, uint64(10), stats[1].HeadLag)
+
+	foundNode, _ := createdChain.getFreeNode(context.Background(), nodeQuery{})
+	assert.Equal(t, chainNode1.name, foundNode.name, "should not route to stale node")
+}
+
+func TestHeadExtractors(t *testing.T) {
+	t.Parallel()
+
+	head, err := BitcoinHeadExtractor{}.Extract(&Response{
+		Body: []byte(`{"result":820000,"error":null,"id":"eznode"}`),
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(820000), head)
+
+	head, err = CosmosHeadExtractor{}.Extract(&Response{
+		Body: []byte(`{"jsonrpc":"2.0","id":-1,"result":{"sync_info":{"latest_block_height":"1234"}}}`),
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1234), head)
+
+	_, err = EthereumHeadExtractor{}.Extract(&Response{
+		Body: []byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"failed"}}`),
+	})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,110 @@
+package eznode
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// HealthCheckConfig determines how nodes of a chain are probed in background
+// nodes which fail probes are taken out of the chain until they pass probes again
+type HealthCheckConfig struct {
+	// Interval is the time between two health checks
+	Interval time.Duration
+	// Timeout of the probe request
+	// Timeout is optional, RequestTimeout of the node is used by default
+	Timeout time.Duration
+	// Method of the probe request, GET is used by default
+	Method string
+	// Path of the probe request, it is appended to the node url like any other request
+	Path string
+	// Body of the probe request
+	// Body is optional
+	Body []byte
+	// Header of the probe request
+	// Header is optional
+	Header http.Header
+	// ExpectedStatusCode is the status code of a healthy node, 200 is used by default
+	ExpectedStatusCode int
+	// Predicate validates the probe response, it is called when status code is as expected
+	// Predicate is optional
+	Predicate func(response *Response) bool
+	// FailureThreshold is number of consecutive failed probes which marks the node unhealthy, 1 is used by default
+	FailureThreshold uint
+	// SuccessThreshold is number of consecutive passed probes which marks the node healthy again, 1 is used by default
+	SuccessThreshold uint
+}
+
+// nodeHealth is protected by the mutex of the chain which owns the node
+type nodeHealth struct {
+	unhealthy bool
+	failures  uint
+	successes uint
+}
+
+func (c *Chain) runHealthCheck(apiCaller ApiCaller, done <-chan struct{}) {
+	ticker := time.NewTicker(c.healthCheck.Interval)
+	defer ticker.Stop()
+
+	for {
+		c.checkNodesHealth(apiCaller)
+
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *Chain) checkNodesHealth(apiCaller ApiCaller) {
+	wg := &sync.WaitGroup{}
+	for _, node := range c.nodes {
+		wg.Add(1)
+		go func(node *ChainNode) {
+			defer wg.Done()
+			c.reportHealth(node, c.isNodeHealthy(apiCaller, node))
+		}(node)
+	}
+	wg.Wait()
+}
+
+func (c *Chain) isNodeHealthy(apiCaller ApiCaller, node *ChainNode) bool {
+	request, err := http.NewRequest(c.healthCheck.Method, c.healthCheck.Path, bytes.NewReader(c.healthCheck.Body))
+	if err != nil {
+		return false
+	}
+	request.Header = c.healthCheck.Header.Clone()
+	if request.Header == nil {
+		request.Header = make(http.Header)
+	}
+
+	res, err := probeNode(context.Background(), apiCaller, node, request, c.healthCheck.Timeout)
+	if err != nil || res.StatusCode != c.healthCheck.ExpectedStatusCode {
+		return false
+	}
+
+	return c.healthCheck.Predicate == nil || c.healthCheck.Predicate(res)
+}
+
+func (c *Chain) reportHealth(node *ChainNode, healthy bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if healthy {
+		node.health.failures = 0
+		node.health.successes += 1
+		if node.health.successes >= c.healthCheck.SuccessThreshold {
+			node.health.unhealthy = false
+		}
+		return
+	}
+
+	node.health.successes = 0
+	node.health.failures += 1
+	if node.health.failures >= c.healthCheck.FailureThreshold {
+		node.health.unhealthy = true
+	}
+}
@@ -0,0 +1,98 @@
+package eznode
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthCheckDisablesAndEnablesNode(t *testing.T) {
+	t.Parallel()
+
+	node2Down := int32(1)
+	mockedApiCall := mockApiCall{
+		returnFunc: func(request *http.Request) (*Response, error) {
+			if request.URL.Host == "example2.com" && atomic.LoadInt32(&node2Down) == 1 {
+				return &Response{
+					StatusCode: http.StatusBadGateway,
+					Headers:    &http.Header{},
+				}, nil
+			}
+
+			return &Response{
+				StatusCode: http.StatusOK,
+				Body:       []byte("ok"),
+				Headers:    &http.Header{},
+			}, nil
+		},
+		validateFunc: func(request *http.Request) {
+			assert.Equal(t, "/health", request.URL.Path)
+		},
+	}
+
+	chainNode1 := NewChainNode(NewChainNodeConfig{
+		Name: "Node 1",
+		Url:  "http://example.com",
+		Limit: ChainNodeLimit{
+			Count: 10,
+			Per:   1 * time.Second,
+		},
+		RequestTimeout: 1 * time.Second,
+		Priority:       1,
+	})
+
+	chainNode2 := NewChainNode(NewChainNodeConfig{
+		Name: "Node 2",
+		Url:  "http://example2.com",
+		Limit: ChainNodeLimit{
+			Count: 10,
+			Per:   1 * time.Second,
+		},
+		RequestTimeout: 1 * time.Second,
+		Priority:       2,
+	})
+
+	createdChain := NewChain(
+		NewChainConfig{
+			Id: "test-chain",
+			Nodes: []*ChainNode{
+				chainNode1,
+				chainNode2,
+			},
+			CheckTickRate: CheckTick{
+				TickRate:         100 * time.Millisecond,
+				MaxCheckDuration: 200 * time.Millisecond,
+			},
+			RetryCount: 2,
+			HealthCheck: &HealthCheckConfig{
+				Interval: 100 * time.Millisecond,
+				Path:     "/health",
+				Predicate: func(response *Response) bool {
+					return string(response.Body) == "ok"
+				},
+			},
+		},
+	)
+
+	ezNode := NewEzNode([]*Chain{createdChain}, WithApiClient(mockedApiCall))
+	defer ezNode.Stop()
+
+	time.Sleep(50 * time.Millisecond)
+	stats := ezNode.GetStats()[0].Nodes
+	assert.True(t, stats[0].Healthy)
+	assert.False(t, stats[1].Healthy)
+
+	foundNode, _ := createdChain.getFreeNode(context.Background(), nodeQuery{})
+	assert.Equal(t, chainNode1.name, foundNode.name, "should not route to unhealthy node")
+
+	atomic.StoreInt32(&node2Down, 0)
+	time.Sleep(150 * time.Millisecond)
+	assert.True(t, ezNode.GetStats()[0].Nodes[1].Healthy)
+
+	foundNode, _ = createdChain.getFreeNode(context.Background(), nodeQuery{})
+	assert.Equal(t, chainNode2.name, foundNode.name, "should route to recovered node")
+}
@@ -2,9 +2,12 @@ package eznode
 
 import (
 	"log"
+	"math"
 	"net/http"
 	"net/url"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -12,8 +15,9 @@ type ChainNode struct {
 	name           string
 	url            *url.URL
 	limit          ChainNodeLimit
+	weight         uint
 	requestTimeout time.Duration
-	hits           uint
+	limiters       []*windowLimiter
 	totalHits      uint64
 	responseStats  map[int]uint64
 	statsMutex     *sync.Mutex
@@ -21,6 +25,18 @@ type ChainNode struct {
 	middleware     RequestMiddleware
 	disabled       bool
 	fails          uint
+	inFlight       int64
+	latency        time.Duration
+	latencySamples []time.Duration
+	latencyCursor  int
+	errorRate      float64
+	breaker        *circuitBreaker
+	health         nodeHealth
+	head           uint64
+	throttledUntil time.Time
+	adaptiveLimit  *adaptiveLimit
+	maxBatchSize   int
+	tags           map[string]bool
 }
 
 // NewChainNodeConfig is parameter to pass to NewChainNode function
@@ -31,53 +47,175 @@ type NewChainNodeConfig struct {
 	Url string
 	// Limit of the node
 	Limit ChainNodeLimit
+	// Limits are additional limits of the node, e.g. per day limit next to per second Limit
+	// a request is sent to the node only when Limit and all of Limits allow it
+	// Limits is optional
+	Limits []ChainNodeLimit
 	// Timeout of a request, if a request timeout, another node will be used
 	RequestTimeout time.Duration
 	// Priority of the node, higher priority will be used first
 	Priority int
+	// Weight of the node among nodes with the same priority, used by weighted selectors
+	// Weight is optional, Limit.Count is used by default
+	Weight uint
 	// Middleware will be used before sending request to the node
 	// you can set up authentication middleware, etc
 	// Middleware is optional
 	Middleware RequestMiddleware
+	// CircuitBreaker takes the node out of the chain when it fails too often
+	// CircuitBreaker is optional
+	CircuitBreaker *CircuitBreakerConfig
+	// AdaptiveLimit grows and cuts back Limit.Count based on node responses
+	// AdaptiveLimit is optional
+	AdaptiveLimit *AdaptiveLimitConfig
+	// MaxBatchSize is max number of calls in a JSON-RPC batch which the node accepts
+	// MaxBatchSize is optional, batches are not limited by default
+	MaxBatchSize int
+	// Tags are capabilities of the node, e.g. archive, trace or debug, they are matched by routes of the chain
+	// Tags is optional
+	Tags []string
+}
+
+// Validate reports every invalid field of the config
+func (c NewChainNodeConfig) Validate() error {
+	errs := fieldErrors{}
+	if c.Name == "" {
+		errs.add("name", "cannot be empty")
+	}
+
+	if _, err := url.Parse(c.Url); err != nil {
+		errs.add("url", err.Error())
+	}
+
+	if c.Limit.Count < 1 {
+		errs.add("limit.count", "cannot be less than 1")
+	}
+
+	if c.Limit.Per < 1 {
+		errs.add("limit.per", "cannot be less than 1")
+	}
+
+	for i, limit := range c.Limits {
+		if limit.Count < 1 {
+			errs.add(indexedField("limits", i)+".count", "cannot be less than 1")
+		}
+
+		if limit.Per < 1 {
+			errs.add(indexedField("limits", i)+".per", "cannot be less than 1")
+		}
+	}
+
+	if c.RequestTimeout < 1 {
+		errs.add("requestTimeout", "cannot be less than 1")
+	}
+
+	if c.Priority < 0 {
+		errs.add("priority", "cannot be less than 0")
+	}
+
+	if c.MaxBatchSize < 0 {
+		errs.add("maxBatchSize", "cannot be less than 0")
+	}
+
+	if c.AdaptiveLimit != nil {
+		if c.AdaptiveLimit.Min < 1 {
+			errs.add("adaptiveLimit.min", "cannot be less than 1")
+		}
+
+		if c.AdaptiveLimit.Max < c.AdaptiveLimit.Min {
+			errs.add("adaptiveLimit.max", "cannot be less than adaptiveLimit.min")
+		}
+
+		if c.Limit.Count < c.AdaptiveLimit.Min || c.Limit.Count > c.AdaptiveLimit.Max {
+			errs.add("limit.count", "must be between adaptiveLimit.min and adaptiveLimit.max")
+		}
+
+		if c.AdaptiveLimit.DecreaseFactor < 0 || c.AdaptiveLimit.DecreaseFactor >= 1 {
+			errs.add("adaptiveLimit.decreaseFactor", "must be between 0 and 1")
+		}
+	}
+
+	if c.CircuitBreaker != nil {
+		if c.CircuitBreaker.FailureThreshold < 1 {
+			errs.add("circuitBreaker.failureThreshold", "cannot be less than 1")
+		}
+
+		if c.CircuitBreaker.Window < 1 {
+			errs.add("circuitBreaker.window", "cannot be less than 1")
+		}
+
+		if c.CircuitBreaker.CoolDown < 1 {
+			errs.add("circuitBreaker.coolDown", "cannot be less than 1")
+		}
+
+		if c.CircuitBreaker.HalfOpenRequests < 1 {
+			errs.add("circuitBreaker.halfOpenRequests", "cannot be less than 1")
+		}
+	}
+
+	for i, tag := range c.Tags {
+		if tag == "" {
+			errs.add(indexedField("tags", i), "cannot be empty")
+		}
+	}
+
+	return errs.err()
 }
 
 // NewChainNode creates a new ChainNode based on the given NewChainParam
+// it calls log.Fatal if the config is invalid, use NewChainNodeE to handle the error
 func NewChainNode(
 	chainNodeData NewChainNodeConfig,
 ) *ChainNode {
-	if chainNodeData.Name == "" {
-		log.Fatal("name cannot be empty")
+	chainNode, err := NewChainNodeE(chainNodeData)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return chainNode
+}
+
+// NewChainNodeE creates a new ChainNode based on the given NewChainParam
+// it returns *ValidationError if the config is invalid
+func NewChainNodeE(
+	chainNodeData NewChainNodeConfig,
+) (*ChainNode, error) {
+	if err := chainNodeData.Validate(); err != nil {
+		return nil, err
 	}
 
 	parsedUrl, err := url.Parse(chainNodeData.Url)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 
-	if chainNodeData.Limit.Count < 1 {
-		log.Fatal("limit.count cannot be less than 1")
+	limiters := []*windowLimiter{newWindowLimiter(chainNodeData.Limit)}
+	for _, limit := range chainNodeData.Limits {
+		limiters = append(limiters, newWindowLimiter(limit))
 	}
 
-	if chainNodeData.Limit.Per < 1 {
-		log.Fatal("limit.per cannot be less than 1")
+	var adaptive *adaptiveLimit
+	if chainNodeData.AdaptiveLimit != nil {
+		adaptive = newAdaptiveLimit(*chainNodeData.AdaptiveLimit, chainNodeData.Limit)
 	}
 
-	if chainNodeData.RequestTimeout < 1 {
-		log.Fatal("requestTimeout cannot be less than 1")
+	var breaker *circuitBreaker
+	if chainNodeData.CircuitBreaker != nil {
+		breaker = newCircuitBreaker(*chainNodeData.CircuitBreaker)
 	}
 
-	if chainNodeData.Priority < 0 {
-		log.Fatal("priority cannot be less than 0")
+	tags := make(map[string]bool)
+	for _, tag := range chainNodeData.Tags {
+		tags[tag] = true
 	}
 
 	middleware := func(request *http.Request) *http.Request {
+		// a url which cannot be joined is left relative, so the request fails instead of the process
 		newParsedUrl, err := url.Parse(parsedUrl.String() + request.URL.String())
-		if err != nil {
-			log.Fatal(err)
+		if err == nil {
+			request.URL = newParsedUrl
 		}
 
-		request.URL = newParsedUrl
-
 		if chainNodeData.Middleware != nil {
 			return chainNodeData.Middleware(request)
 		}
@@ -85,17 +223,187 @@ func NewChainNode(
 		return request
 	}
 
+	weight := chainNodeData.Weight
+	if weight == 0 {
+		weight = chainNodeData.Limit.Count
+	}
+
 	return &ChainNode{
 		name:           chainNodeData.Name,
 		url:            parsedUrl,
 		limit:          chainNodeData.Limit,
+		weight:         weight,
 		requestTimeout: chainNodeData.RequestTimeout,
-		hits:           0,
+		limiters:       limiters,
 		totalHits:      0,
 		responseStats:  make(map[int]uint64),
 		statsMutex:     &sync.Mutex{},
 		priority:       chainNodeData.Priority,
 		middleware:     middleware,
 		disabled:       false,
+		breaker:        breaker,
+		adaptiveLimit:  adaptive,
+		maxBatchSize:   chainNodeData.MaxBatchSize,
+		tags:           tags,
+	}, nil
+}
+
+// hasTags reports whether the node has all of the tags
+func (n *ChainNode) hasTags(tags map[string]bool) bool {
+	for tag := range tags {
+		if !n.tags[tag] {
+			return false
+		}
 	}
+
+	return true
+}
+
+// isAvailable reports whether the node can receive requests, it must be called while the chain is locked
+func (n *ChainNode) isAvailable(now time.Time) bool {
+	if n.disabled || n.health.unhealthy || now.Before(n.throttledUntil) {
+		return false
+	}
+
+	return n.breaker == nil || n.breaker.allow(now)
+}
+
+// circuitState returns the state of the node circuit breaker, it must be called while the chain is locked
+func (n *ChainNode) circuitState(now time.Time) CircuitState {
+	if n.breaker == nil {
+		return CircuitClosed
+	}
+
+	return n.breaker.currentState(now)
+}
+
+// Name returns the name of the node
+func (n *ChainNode) Name() string {
+	return n.name
+}
+
+// Priority returns the priority of the node
+func (n *ChainNode) Priority() int {
+	return n.priority
+}
+
+// Weight returns the weight of the node
+func (n *ChainNode) Weight() uint {
+	return n.weight
+}
+
+// hits returns the number of requests which the node limit has not recovered from yet
+// it must be called while the chain is locked, e.g. from a Selector
+func (n *ChainNode) hits() uint {
+	return n.limiters[0].used(time.Now())
+}
+
+// allowRequest reports whether all limits of the node allow a request
+// reserve is the fraction of the limits which the request cannot use, it must be called while the chain is locked
+func (n *ChainNode) allowRequest(now time.Time, cost float64, reserve float64) bool {
+	for _, limiter := range n.limiters {
+		if !limiter.allow(now, cost, reserve) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// takeRequest consumes a request from all limits of the node, it must be called while the chain is locked
+func (n *ChainNode) takeRequest(now time.Time, cost float64) {
+	for _, limiter := range n.limiters {
+		limiter.take(now, cost)
+	}
+}
+
+// giveBackRequest returns a request which is not sent to all limits of the node, it must be called while the chain is locked
+func (n *ChainNode) giveBackRequest(now time.Time, cost float64) {
+	for _, limiter := range n.limiters {
+		limiter.giveBack(now, cost)
+	}
+}
+
+// timeUntilAllowed returns how long it takes until all limits of the node allow a request
+// it is negative when the cost never fits, it must be called while the chain is locked
+func (n *ChainNode) timeUntilAllowed(now time.Time, cost float64, reserve float64) time.Duration {
+	wait := time.Duration(0)
+	for _, limiter := range n.limiters {
+		limiterWait := limiter.timeUntil(now, cost, reserve)
+		if limiterWait < 0 {
+			return -1
+		}
+		wait = max(wait, limiterWait)
+	}
+
+	return wait
+}
+
+// limitStats returns usage of all limits of the node, it must be called while the chain is locked
+func (n *ChainNode) limitStats(now time.Time) []ChainNodeLimitStats {
+	limitStats := make([]ChainNodeLimitStats, 0, len(n.limiters))
+	for _, limiter := range n.limiters {
+		limitStats = append(limitStats, ChainNodeLimitStats{
+			Count: limiter.limit.Count,
+			Per:   limiter.limit.Per,
+			Burst: limiter.limit.Burst,
+			Used:  limiter.used(now),
+		})
+	}
+
+	return limitStats
+}
+
+// InFlight returns the number of requests which are being processed by the node
+func (n *ChainNode) InFlight() int64 {
+	return atomic.LoadInt64(&n.inFlight)
+}
+
+// Latency returns the exponentially weighted moving average of the node response time
+// zero means the node has not responded yet
+func (n *ChainNode) Latency() time.Duration {
+	n.statsMutex.Lock()
+	defer n.statsMutex.Unlock()
+
+	return n.latency
+}
+
+// latencySampleSize is the number of recent response times kept for percentiles
+const latencySampleSize = 128
+
+// addLatencySample must be called while statsMutex is locked
+func (n *ChainNode) addLatencySample(duration time.Duration) {
+	if len(n.latencySamples) < latencySampleSize {
+		n.latencySamples = append(n.latencySamples, duration)
+		return
+	}
+
+	n.latencySamples[n.latencyCursor] = duration
+	n.latencyCursor = (n.latencyCursor + 1) % latencySampleSize
+}
+
+// latencyPercentile returns the percentile of recent response times, percentile is between 0 and 1
+// it returns false when the node has less than minSamples samples
+func (n *ChainNode) latencyPercentile(percentile float64, minSamples int) (time.Duration, bool) {
+	n.statsMutex.Lock()
+	samples := slices.Clone(n.latencySamples)
+	n.statsMutex.Unlock()
+
+	if len(samples) == 0 || len(samples) < minSamples {
+		return 0, false
+	}
+
+	slices.Sort(samples)
+	index := int(math.Ceil(percentile*float64(len(samples)))) - 1
+	index = max(0, min(index, len(samples)-1))
+
+	return samples[index], true
+}
+
+// ErrorRate returns the exponentially weighted moving average of the node failures, between 0 and 1
+func (n *ChainNode) ErrorRate() float64 {
+	n.statsMutex.Lock()
+	defer n.statsMutex.Unlock()
+
+	return n.errorRate
 }
@@ -0,0 +1,61 @@
+package eznode
+
+import (
+	"math"
+	"net/http"
+	"time"
+)
+
+// AdaptiveLimitConfig lets a node discover its real limit, e.g. limit of a public node which is unknown
+// Limit.Count of the node is the starting point, it grows while the node responds successfully
+// and it is cut back when the node responds 429 or times out (AIMD)
+type AdaptiveLimitConfig struct {
+	// Min is the lowest count the limit can be cut back to
+	Min uint
+	// Max is the highest count the limit can grow to
+	Max uint
+	// Increase is added to the count after as many successful responses as the count, 1 is used by default
+	Increase uint
+	// DecreaseFactor multiplies the count when the node is overloaded, between 0 and 1, 0.5 is used by default
+	DecreaseFactor float64
+}
+
+// adaptiveLimit is protected by the mutex of the chain which owns the node
+type adaptiveLimit struct {
+	config        AdaptiveLimitConfig
+	count         float64
+	decreasedAt   time.Time
+	decreaseDelay time.Duration
+}
+
+func newAdaptiveLimit(config AdaptiveLimitConfig, limit ChainNodeLimit) *adaptiveLimit {
+	if config.Increase == 0 {
+		config.Increase = 1
+	}
+
+	if config.DecreaseFactor == 0 {
+		config.DecreaseFactor = 0.5
+	}
+
+	return &adaptiveLimit{
+		config: config,
+		count:  float64(limit.Count),
+		// responses of requests which were sent before a decrease should not decrease again
+		decreaseDelay: limit.Per,
+	}
+}
+
+// update returns the new count of the limit after the result of a request
+func (a *adaptiveLimit) update(now time.Time, res *Response, err error, isValid bool) uint {
+	overloaded := isTimeoutError(err) || (res != nil && res.StatusCode == http.StatusTooManyRequests)
+	if overloaded {
+		if now.Sub(a.decreasedAt) >= a.decreaseDelay {
+			a.count = math.Max(float64(a.config.Min), math.Floor(a.count*a.config.DecreaseFactor))
+			a.decreasedAt = now
+		}
+	} else if isValid {
+		a.count = math.Min(float64(a.config.Max), a.count+float64(a.config.Increase)/a.count)
+	}
+
+	return uint(a.count)
+}
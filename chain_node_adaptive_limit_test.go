@@ -0,0 +1,64 @@
+package eznode
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdaptiveLimit(t *testing.T) {
+	t.Parallel()
+
+	chainNode1 := NewChainNode(NewChainNodeConfig{
+		Name: "Node 1",
+		Url:  "http://example.com",
+		Limit: ChainNodeLimit{
+			Count: 10,
+			Per:   1 * time.Second,
+		},
+		RequestTimeout: 1 * time.Second,
+		Priority:       1,
+		AdaptiveLimit: &AdaptiveLimitConfig{
+			Min: 4,
+			Max: 11,
+		},
+	})
+
+	createdChain := NewChain(
+		NewChainConfig{
+			Id: "test-chain",
+			Nodes: []*ChainNode{
+				chainNode1,
+			},
+			CheckTickRate: CheckTick{
+				TickRate:         100 * time.Millisecond,
+				MaxCheckDuration: 200 * time.Millisecond,
+			},
+			RetryCount: 2,
+		},
+	)
+
+	okResponse := &Response{StatusCode: http.StatusOK}
+	for i := 0; i < 11; i++ {
+		createdChain.reportResult(chainNode1, okResponse, nil, true)
+	}
+	assert.Equal(t, uint(11), createdChain.getStats()[0].EffectiveLimit, "should grow after successful responses")
+
+	for i := 0; i < 20; i++ {
+		createdChain.reportResult(chainNode1, okResponse, nil, true)
+	}
+	assert.Equal(t, uint(11), createdChain.getStats()[0].EffectiveLimit, "should not grow more than max")
+
+	createdChain.reportResult(chainNode1, &Response{StatusCode: http.StatusTooManyRequests}, nil, false)
+	assert.Equal(t, uint(5), createdChain.getStats()[0].EffectiveLimit, "should cut back on 429")
+
+	createdChain.reportResult(chainNode1, nil, context.DeadlineExceeded, false)
+	assert.Equal(t, uint(5), createdChain.getStats()[0].EffectiveLimit, "should cut back once per limit period")
+
+	time.Sleep(1 * time.Second)
+	createdChain.reportResult(chainNode1, nil, context.DeadlineExceeded, false)
+	assert.Equal(t, uint(4), createdChain.getStats()[0].EffectiveLimit, "should not cut back less than min")
+}
@@ -0,0 +1,135 @@
+package eznode
+
+import "time"
+
+// CircuitState is the state of a node circuit breaker
+type CircuitState int
+
+const (
+	// CircuitClosed means the node receives requests normally
+	CircuitClosed CircuitState = iota
+	// CircuitOpen means the node failed too often and does not receive requests until cool-down passes
+	CircuitOpen
+	// CircuitHalfOpen means the cool-down passed and a limited number of probe requests are sent to the node
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// CircuitBreakerConfig determines when a node is taken out of the chain automatically
+type CircuitBreakerConfig struct {
+	// FailureThreshold is number of failures within Window which opens the circuit
+	FailureThreshold uint
+	// Window is the rolling window which failures are counted in
+	Window time.Duration
+	// CoolDown is how long the circuit stays open before probing the node again
+	CoolDown time.Duration
+	// HalfOpenRequests is number of probe requests sent in half-open state
+	// the circuit closes when all of them succeed and opens again on the first failure
+	HalfOpenRequests uint
+}
+
+// circuitBreaker is protected by the mutex of the chain which owns the node
+type circuitBreaker struct {
+	config            CircuitBreakerConfig
+	state             CircuitState
+	failures          []time.Time
+	openedAt          time.Time
+	halfOpenSent      uint
+	halfOpenSucceeded uint
+}
+
+func newCircuitBreaker(config CircuitBreakerConfig) *circuitBreaker {
+	return &circuitBreaker{
+		config:   config,
+		state:    CircuitClosed,
+		failures: make([]time.Time, 0),
+	}
+}
+
+func (b *circuitBreaker) currentState(now time.Time) CircuitState {
+	if b.state == CircuitOpen && !now.Before(b.openedAt.Add(b.config.CoolDown)) {
+		b.state = CircuitHalfOpen
+		b.halfOpenSent = 0
+		b.halfOpenSucceeded = 0
+	}
+
+	return b.state
+}
+
+func (b *circuitBreaker) allow(now time.Time) bool {
+	switch b.currentState(now) {
+	case CircuitOpen:
+		return false
+	case CircuitHalfOpen:
+		return b.halfOpenSent < b.config.HalfOpenRequests
+	default:
+		return true
+	}
+}
+
+func (b *circuitBreaker) onSelected() {
+	if b.state == CircuitHalfOpen {
+		b.halfOpenSent += 1
+	}
+}
+
+// onAbandoned gives back the probe slot of a request whose result says nothing about the node
+func (b *circuitBreaker) onAbandoned() {
+	if b.state == CircuitHalfOpen && b.halfOpenSent > 0 {
+		b.halfOpenSent -= 1
+	}
+}
+
+func (b *circuitBreaker) recordSuccess() {
+	if b.state != CircuitHalfOpen {
+		return
+	}
+
+	b.halfOpenSucceeded += 1
+	if b.halfOpenSucceeded >= b.config.HalfOpenRequests {
+		b.reset()
+	}
+}
+
+func (b *circuitBreaker) recordFailure(now time.Time) {
+	switch b.state {
+	case CircuitHalfOpen:
+		b.open(now)
+	case CircuitClosed:
+		windowStart := now.Add(-b.config.Window)
+		failures := b.failures[:0]
+		for _, failure := range b.failures {
+			if failure.After(windowStart) {
+				failures = append(failures, failure)
+			}
+		}
+		b.failures = append(failures, now)
+
+		if uint(len(b.failures)) >= b.config.FailureThreshold {
+			b.open(now)
+		}
+	}
+}
+
+func (b *circuitBreaker) open(now time.Time) {
+	b.state = CircuitOpen
+	b.openedAt = now
+	b.failures = b.failures[:0]
+}
+
+func (b *circuitBreaker) reset() {
+	b.state = CircuitClosed
+	b.failures = b.failures[:0]
+	b.halfOpenSent = 0
+	b.halfOpenSucceeded = 0
+}
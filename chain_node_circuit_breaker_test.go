@@ -0,0 +1,196 @@
+package eznode
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCircuitBreakerOpensAndRecovers(t *testing.T) {
+	t.Parallel()
+
+	shouldFail := true
+	mockedApiCall := mockApiCall{
+		returnFunc: func(request *http.Request) (*Response, error) {
+			if shouldFail {
+				return nil, errors.New("connection refused")
+			}
+
+			return &Response{
+				StatusCode: 200,
+				Headers:    &http.Header{},
+			}, nil
+		},
+		validateFunc: func(request *http.Request) {
+		},
+	}
+
+	chainNode1 := NewChainNode(NewChainNodeConfig{
+		Name: "Node 1",
+		Url:  "http://example.com",
+		Limit: ChainNodeLimit{
+			Count: 100,
+			Per:   1 * time.Second,
+		},
+		RequestTimeout: 1 * time.Second,
+		Priority:       1,
+		CircuitBreaker: &CircuitBreakerConfig{
+			FailureThreshold: 2,
+			Window:           10 * time.Second,
+			CoolDown:         500 * time.Millisecond,
+			HalfOpenRequests: 1,
+		},
+	})
+
+	createdChain := NewChain(
+		NewChainConfig{
+			Id: "test-chain",
+			Nodes: []*ChainNode{
+				chainNode1,
+			},
+			CheckTickRate: CheckTick{
+				TickRate:         100 * time.Millisecond,
+				MaxCheckDuration: 200 * time.Millisecond,
+			},
+			RetryCount: 2,
+		},
+	)
+
+	ezNode := NewEzNode([]*Chain{createdChain}, WithApiClient(mockedApiCall))
+	request, _ := http.NewRequest("GET", "/", nil)
+
+	ezNode.SendRequest(context.Background(), "test-chain", request)
+	assert.Equal(t, "closed", ezNode.GetStats()[0].Nodes[0].CircuitState)
+
+	ezNode.SendRequest(context.Background(), "test-chain", request)
+	stats := ezNode.GetStats()[0].Nodes[0]
+	assert.Equal(t, "open", stats.CircuitState)
+	assert.True(t, stats.Disabled)
+
+	shouldFail = false
+	_, err := ezNode.SendRequest(context.Background(), "test-chain", request)
+	assert.NotNil(t, err, "should not send request to open circuit")
+
+	time.Sleep(500 * time.Millisecond)
+	assert.Equal(t, "half-open", ezNode.GetStats()[0].Nodes[0].CircuitState)
+
+	res, err := ezNode.SendRequest(context.Background(), "test-chain", request)
+	assert.Nil(t, err)
+	assert.Equal(t, 200, res.StatusCode)
+	assert.Equal(t, "closed", ezNode.GetStats()[0].Nodes[0].CircuitState)
+}
+
+func TestEnableNodeClosesCircuit(t *testing.T) {
+	t.Parallel()
+
+	chainNode1 := NewChainNode(NewChainNodeConfig{
+		Name: "Node 1",
+		Url:  "http://example.com",
+		Limit: ChainNodeLimit{
+			Count: 10,
+			Per:   1 * time.Second,
+		},
+		RequestTimeout: 1 * time.Second,
+		Priority:       1,
+		CircuitBreaker: &CircuitBreakerConfig{
+			FailureThreshold: 1,
+			Window:           10 * time.Second,
+			CoolDown:         time.Minute,
+			HalfOpenRequests: 1,
+		},
+	})
+
+	createdChain := NewChain(
+		NewChainConfig{
+			Id: "test-chain",
+			Nodes: []*ChainNode{
+				chainNode1,
+			},
+			CheckTickRate: CheckTick{
+				TickRate:         100 * time.Millisecond,
+				MaxCheckDuration: 200 * time.Millisecond,
+			},
+			RetryCount: 2,
+		},
+	)
+
+	createdChain.reportResult(chainNode1, nil, nil, false)
+	foundNode, _ := createdChain.getFreeNode(context.Background(), nodeQuery{})
+	assert.Nil(t, foundNode, "should not find node")
+
+	createdChain.enableNode("Node 1")
+	foundNode, _ = createdChain.getFreeNode(context.Background(), nodeQuery{})
+	assert.NotNil(t, foundNode, "should find node")
+}
+
+func TestCancelledHalfOpenProbe(t *testing.T) {
+	t.Parallel()
+
+	mockedApiCall := contextApiCall(func(ctx context.Context, request *http.Request) (*Response, error) {
+		if request.URL.Path == "/slow" {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+
+		return &Response{
+			StatusCode: 200,
+			Headers:    &http.Header{},
+		}, nil
+	})
+
+	chainNode1 := NewChainNode(NewChainNodeConfig{
+		Name: "Node 1",
+		Url:  "http://example.com",
+		Limit: ChainNodeLimit{
+			Count: 100,
+			Per:   1 * time.Second,
+		},
+		RequestTimeout: 1 * time.Second,
+		Priority:       1,
+		CircuitBreaker: &CircuitBreakerConfig{
+			FailureThreshold: 1,
+			Window:           10 * time.Second,
+			CoolDown:         100 * time.Millisecond,
+			HalfOpenRequests: 1,
+		},
+	})
+
+	createdChain := NewChain(
+		NewChainConfig{
+			Id: "test-chain",
+			Nodes: []*ChainNode{
+				chainNode1,
+			},
+			CheckTickRate: CheckTick{
+				TickRate:         100 * time.Millisecond,
+				MaxCheckDuration: 200 * time.Millisecond,
+			},
+		},
+	)
+
+	ezNode := NewEzNode([]*Chain{createdChain}, WithApiClient(mockedApiCall))
+	createdChain.reportResult(chainNode1, nil, nil, false)
+	assert.Equal(t, "open", ezNode.GetStats()[0].Nodes[0].CircuitState)
+	time.Sleep(100 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+	slowRequest, _ := http.NewRequest("GET", "/slow", nil)
+	_, err := ezNode.SendRequest(ctx, "test-chain", slowRequest)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, "half-open", ezNode.GetStats()[0].Nodes[0].CircuitState)
+
+	request, _ := http.NewRequest("GET", "/", nil)
+	res, err := ezNode.SendRequest(context.Background(), "test-chain", request)
+	assert.Nil(t, err, "cancelled probe should give its slot back")
+	assert.Equal(t, 200, res.StatusCode)
+	assert.Equal(t, "closed", ezNode.GetStats()[0].Nodes[0].CircuitState)
+}
@@ -1,13 +1,186 @@
 package eznode
 
 import (
+	"math"
 	"time"
 )
 
+// ChainNodeLimitStats is the usage of a node limit
+type ChainNodeLimitStats struct {
+	Count uint          `json:"count"`
+	Per   time.Duration `json:"per"`
+	Burst uint          `json:"burst"`
+	Used  uint          `json:"used"`
+}
+
 // ChainNodeLimit determine the limit of node, how many request can be sent to one node
 type ChainNodeLimit struct {
 	// Count is max number of request can be sent to this node per Per
 	Count uint
 	// Per is time period of the limit
 	Per time.Duration
+	// Burst is max number of request can be sent to this node at once, the rest of Count is spread at Count per Per
+	// Burst is optional, the whole Count can be sent at once by default
+	Burst uint
+}
+
+// limitSlots is the number of slots which a window of the limit is split into
+// takes of a slot are released together, Per after the last of them, so the limit never admits more than Count per Per
+const limitSlots = 64
+
+// limitSlot is the cost taken by requests which are sent close to each other
+type limitSlot struct {
+	start time.Time
+	last  time.Time
+	cost  float64
+}
+
+// windowLimiter enforces a ChainNodeLimit over a sliding window of Per
+// Burst is enforced by a bucket which refills continuously at Count per Per
+// it is protected by the mutex of the chain which owns the node
+type windowLimiter struct {
+	limit          ChainNodeLimit
+	slots          []limitSlot
+	burstTokens    float64
+	burstUpdatedAt time.Time
+}
+
+func newWindowLimiter(limit ChainNodeLimit) *windowLimiter {
+	return &windowLimiter{
+		limit:          limit,
+		slots:          make([]limitSlot, 0, limitSlots),
+		burstTokens:    float64(limit.Burst),
+		burstUpdatedAt: time.Now(),
+	}
+}
+
+func (l *windowLimiter) capacity() float64 {
+	return float64(l.limit.Count)
+}
+
+// windowUsed returns the cost which is not released yet
+func (l *windowLimiter) windowUsed(now time.Time) float64 {
+	used := 0.0
+	for _, slot := range l.slots {
+		if now.Before(slot.last.Add(l.limit.Per)) {
+			used += slot.cost
+		}
+	}
+
+	return used
+}
+
+func (l *windowLimiter) burstAvailable(now time.Time) float64 {
+	elapsed := now.Sub(l.burstUpdatedAt)
+	if elapsed <= 0 {
+		return l.burstTokens
+	}
+
+	rate := float64(l.limit.Count) / float64(l.limit.Per)
+	return math.Min(float64(l.limit.Burst), l.burstTokens+float64(elapsed)*rate)
+}
+
+// reserved returns cost which a request cannot use when reserve fraction of Count stays untouched
+// it leaves room for at least one request of cost, so small limits still serve reserved requests
+func (l *windowLimiter) reserved(cost float64, reserve float64) float64 {
+	return math.Max(0, math.Min(reserve*l.capacity(), l.capacity()-cost))
+}
+
+// allow reports whether cost is available while reserve fraction of Count stays untouched
+func (l *windowLimiter) allow(now time.Time, cost float64, reserve float64) bool {
+	if l.windowUsed(now)+cost+l.reserved(cost, reserve) > l.capacity() {
+		return false
+	}
+
+	return l.limit.Burst == 0 || l.burstAvailable(now) >= cost
+}
+
+// take records cost at now, usage may go beyond Count when cost is taken without allow
+func (l *windowLimiter) take(now time.Time, cost float64) {
+	released := 0
+	for released < len(l.slots) && !now.Before(l.slots[released].last.Add(l.limit.Per)) {
+		released += 1
+	}
+	l.slots = append(l.slots[:0], l.slots[released:]...)
+
+	if last := len(l.slots) - 1; last >= 0 && now.Sub(l.slots[last].start) < l.limit.Per/limitSlots {
+		l.slots[last].cost += cost
+		if now.After(l.slots[last].last) {
+			l.slots[last].last = now
+		}
+	} else {
+		l.slots = append(l.slots, limitSlot{start: now, last: now, cost: cost})
+	}
+
+	if l.limit.Burst > 0 {
+		l.burstTokens = l.burstAvailable(now) - cost
+		l.burstUpdatedAt = now
+	}
+}
+
+// giveBack returns cost which was taken by a request which is not sent
+func (l *windowLimiter) giveBack(now time.Time, cost float64) {
+	remaining := cost
+	for i := len(l.slots) - 1; i >= 0 && remaining > 0; i-- {
+		given := math.Min(remaining, l.slots[i].cost)
+		l.slots[i].cost -= given
+		remaining -= given
+	}
+
+	if l.limit.Burst > 0 {
+		l.burstTokens = math.Min(float64(l.limit.Burst), l.burstAvailable(now)+cost)
+		l.burstUpdatedAt = now
+	}
+}
+
+// timeUntil returns how long it takes until allow reports true, it is negative when it never does
+func (l *windowLimiter) timeUntil(now time.Time, cost float64, reserve float64) time.Duration {
+	required := cost + l.reserved(cost, reserve)
+	if required > l.capacity() || (l.limit.Burst > 0 && cost > float64(l.limit.Burst)) {
+		return -1
+	}
+
+	wait := time.Duration(0)
+	excess := l.windowUsed(now) + required - l.capacity()
+	for _, slot := range l.slots {
+		if excess <= 0 {
+			break
+		}
+
+		releasedAt := slot.last.Add(l.limit.Per)
+		if !now.Before(releasedAt) {
+			continue
+		}
+
+		excess -= slot.cost
+		wait = releasedAt.Sub(now)
+	}
+
+	if l.limit.Burst > 0 {
+		if missing := cost - l.burstAvailable(now); missing > 0 {
+			rate := float64(l.limit.Count) / float64(l.limit.Per)
+			wait = max(wait, time.Duration(math.Ceil(missing/rate)))
+		}
+	}
+
+	return wait
+}
+
+// setCount changes Count of the limit, cost which is already taken stays in the window
+func (l *windowLimiter) setCount(now time.Time, count uint) {
+	if count == l.limit.Count {
+		return
+	}
+
+	if l.limit.Burst > 0 {
+		l.burstTokens = l.burstAvailable(now)
+		l.burstUpdatedAt = now
+	}
+	l.limit.Count = count
+}
+
+// used returns the cost which is not released yet
+func (l *windowLimiter) used(now time.Time) uint {
+	// tolerate float rounding, so a given back cost is not reported as used
+	return uint(math.Max(0, math.Ceil(l.windowUsed(now)-1e-9)))
 }
@@ -0,0 +1,61 @@
+package eznode
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimitNeverAdmitsMoreThanCountPerWindow(t *testing.T) {
+	t.Parallel()
+
+	limiter := newWindowLimiter(ChainNodeLimit{
+		Count: 10,
+		Per:   time.Second,
+	})
+
+	start := time.Now()
+	takes := make([]time.Time, 0)
+	for now := start; now.Before(start.Add(5 * time.Second)); now = now.Add(time.Millisecond) {
+		if limiter.allow(now, 1, 0) {
+			limiter.take(now, 1)
+			takes = append(takes, now)
+		}
+	}
+
+	for i := range takes {
+		inWindow := 0
+		for _, take := range takes[i:] {
+			if take.Sub(takes[i]) < time.Second {
+				inWindow += 1
+			}
+		}
+		assert.LessOrEqual(t, inWindow, 10, "should not admit more than Count in any window of Per")
+	}
+	assert.GreaterOrEqual(t, len(takes), 40, "should release capacity once Per passes")
+}
+
+func TestLimitBurst(t *testing.T) {
+	t.Parallel()
+
+	limiter := newWindowLimiter(ChainNodeLimit{
+		Count: 10,
+		Per:   time.Second,
+		Burst: 2,
+	})
+
+	now := time.Now()
+	assert.True(t, limiter.allow(now, 1, 0))
+	limiter.take(now, 1)
+	assert.True(t, limiter.allow(now, 1, 0))
+	limiter.take(now, 1)
+	assert.False(t, limiter.allow(now, 1, 0), "should not send more than Burst at once")
+	assert.Equal(t, 100*time.Millisecond, limiter.timeUntil(now, 1, 0))
+	assert.True(t, limiter.allow(now.Add(100*time.Millisecond), 1, 0))
+	assert.Equal(t, time.Duration(-1), limiter.timeUntil(now, 3, 0), "cost above Burst never fits")
+
+	limiter.giveBack(now, 1)
+	assert.Equal(t, uint(1), limiter.used(now))
+	assert.True(t, limiter.allow(now, 1, 0))
+}
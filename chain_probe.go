@@ -0,0 +1,25 @@
+package eznode
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// probeNode sends a request to the node outside the load balancer, it does not consume the node limit
+// Note: request should not have host, schema and port, like requests which are passed to SendRequest
+func probeNode(
+	ctx context.Context,
+	apiCaller ApiCaller,
+	node *ChainNode,
+	request *http.Request,
+	timeout time.Duration,
+) (*Response, error) {
+	if timeout <= 0 {
+		timeout = node.requestTimeout
+	}
+	ctxTimeout, cancelTimeout := context.WithTimeout(ctx, timeout)
+	defer cancelTimeout()
+
+	return apiCaller.DoRequest(ctxTimeout, node.middleware(request))
+}
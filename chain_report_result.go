@@ -0,0 +1,62 @@
+package eznode
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// reportAbandoned gives back what selecting the node reserved when the result of the request says nothing about it
+func (c *Chain) reportAbandoned(node *ChainNode) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if node.breaker != nil {
+		node.breaker.onAbandoned()
+		c.dispatchLocked()
+	}
+}
+
+// releaseNode gives back a node which is taken for a request but not sent it
+// cost is what taking the node consumed from its limits, i.e. nodeQuery.requestCost
+func (c *Chain) releaseNode(node *ChainNode, cost float64) {
+	atomic.AddInt64(&node.inFlight, -1)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	node.giveBackRequest(time.Now(), cost)
+	if node.breaker != nil {
+		node.breaker.onAbandoned()
+	}
+	c.dispatchLocked()
+}
+
+// reportResult updates the node state which depends on the result of a request
+func (c *Chain) reportResult(node *ChainNode, res *Response, err error, isValid bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := time.Now()
+	if node.breaker != nil {
+		if isValid {
+			node.breaker.recordSuccess()
+		} else {
+			node.breaker.recordFailure(now)
+		}
+	}
+
+	if node.adaptiveLimit != nil {
+		node.limiters[0].setCount(now, node.adaptiveLimit.update(now, res, err, isValid))
+	}
+
+	if retryAfter, ok := parseRetryAfter(res, now); ok {
+		if c.maxRetryAfter > 0 && retryAfter > c.maxRetryAfter {
+			retryAfter = c.maxRetryAfter
+		}
+
+		throttledUntil := now.Add(retryAfter)
+		if throttledUntil.After(node.throttledUntil) {
+			node.throttledUntil = throttledUntil
+		}
+	}
+}
@@ -12,6 +12,12 @@ type ChainResponseMetadata struct {
 	Retry int
 	// Trace is request to response trace
 	Trace []NodeTrace
+	// Cached reports whether the response is served from the cache, Trace is empty then
+	Cached bool
+	// Coalesced reports whether the response is shared from an identical request which was in flight, see WithCoalescing
+	Coalesced bool
+	// Quorum is the answer of every node of a SendRequestQuorum request, it is nil for other requests
+	Quorum *QuorumMetadata
 }
 
 // NodeTrace is a structure that contains the trace of a request
@@ -24,4 +30,8 @@ type NodeTrace struct {
 	Err error
 	// Time is the time that the request was sent
 	Time time.Time
+	// Duration is how long the node took to respond
+	Duration time.Duration
+	// Wait is how long the request waited by RetryPolicy before this attempt
+	Wait time.Duration
 }
@@ -0,0 +1,88 @@
+package eznode
+
+import (
+	"sync"
+	"time"
+)
+
+// RetryBudgetConfig caps retries of a chain relative to first attempts, so an outage does not multiply load
+// e.g. Ratio 0.2 allows retries up to 20% of requests within Window
+type RetryBudgetConfig struct {
+	// Ratio is max number of retries per first attempt within Window
+	Ratio float64
+	// Window is the sliding window which attempts and retries are counted in
+	Window time.Duration
+	// MinRetries is number of retries which are always allowed within Window, so low traffic can still retry
+	// MinRetries is optional
+	MinRetries uint
+}
+
+// retryBudgetBuckets is the number of buckets which the sliding window is split into
+const retryBudgetBuckets = 10
+
+type retryBudgetBucket struct {
+	start    time.Time
+	attempts uint64
+	retries  uint64
+}
+
+type retryBudget struct {
+	config  RetryBudgetConfig
+	mutex   *sync.Mutex
+	buckets []retryBudgetBucket
+}
+
+func newRetryBudget(config RetryBudgetConfig) *retryBudget {
+	return &retryBudget{
+		config:  config,
+		mutex:   &sync.Mutex{},
+		buckets: make([]retryBudgetBucket, retryBudgetBuckets),
+	}
+}
+
+// bucket returns the bucket of now, it must be called while the budget is locked
+func (b *retryBudget) bucket(now time.Time) *retryBudgetBucket {
+	bucketDuration := b.config.Window / retryBudgetBuckets
+	if bucketDuration <= 0 {
+		bucketDuration = 1
+	}
+
+	start := now.Truncate(bucketDuration)
+	bucket := &b.buckets[(start.UnixNano()/int64(bucketDuration))%retryBudgetBuckets]
+	if !bucket.start.Equal(start) {
+		*bucket = retryBudgetBucket{start: start}
+	}
+
+	return bucket
+}
+
+func (b *retryBudget) recordAttempt(now time.Time) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.bucket(now).attempts += 1
+}
+
+// tryRetry reports whether a retry is allowed and counts it if so
+func (b *retryBudget) tryRetry(now time.Time) bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	current := b.bucket(now)
+	windowStart := now.Add(-b.config.Window)
+	attempts := uint64(0)
+	retries := uint64(0)
+	for _, bucket := range b.buckets {
+		if bucket.start.After(windowStart) {
+			attempts += bucket.attempts
+			retries += bucket.retries
+		}
+	}
+
+	if float64(retries+1) > b.config.Ratio*float64(attempts)+float64(b.config.MinRetries) {
+		return false
+	}
+
+	current.retries += 1
+	return true
+}
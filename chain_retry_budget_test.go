@@ -0,0 +1,81 @@
+package eznode
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryBudget(t *testing.T) {
+	t.Parallel()
+
+	mockedApiCall := mockApiCall{
+		returnFunc: func(request *http.Request) (*Response, error) {
+			return nil, errors.New("error")
+		},
+		validateFunc: func(request *http.Request) {
+		},
+	}
+
+	chainNode1 := NewChainNode(NewChainNodeConfig{
+		Name: "Node 1",
+		Url:  "http://example.com",
+		Limit: ChainNodeLimit{
+			Count: 10,
+			Per:   1 * time.Second,
+		},
+		RequestTimeout: 1 * time.Second,
+		Priority:       1,
+	})
+
+	chainNode2 := NewChainNode(NewChainNodeConfig{
+		Name: "Node 2",
+		Url:  "http://example2.com",
+		Limit: ChainNodeLimit{
+			Count: 10,
+			Per:   1 * time.Second,
+		},
+		RequestTimeout: 1 * time.Second,
+		Priority:       1,
+	})
+
+	createdChain := NewChain(
+		NewChainConfig{
+			Id: "test-chain",
+			Nodes: []*ChainNode{
+				chainNode1,
+				chainNode2,
+			},
+			CheckTickRate: CheckTick{
+				TickRate:         100 * time.Millisecond,
+				MaxCheckDuration: 200 * time.Millisecond,
+			},
+			RetryCount: 1,
+			RetryBudget: &RetryBudgetConfig{
+				Ratio:  0.5,
+				Window: 1 * time.Minute,
+			},
+		},
+	)
+
+	ezNode := NewEzNode([]*Chain{createdChain}, WithApiClient(mockedApiCall))
+	request, _ := http.NewRequest("GET", "/", nil)
+
+	expectedKinds := []ErrorKind{
+		ErrorKindRetryBudgetExhausted,
+		ErrorKindMaxRetries,
+		ErrorKindRetryBudgetExhausted,
+		ErrorKindMaxRetries,
+	}
+	for _, expectedKind := range expectedKinds {
+		_, err := ezNode.SendRequest(context.Background(), "test-chain", request)
+
+		var ezNodeError EzNodeError
+		assert.True(t, errors.As(err, &ezNodeError))
+		assert.Equal(t, expectedKind, ezNodeError.Kind)
+	}
+}
@@ -0,0 +1,57 @@
+package eznode
+
+import (
+	"net/http"
+	"path"
+	"slices"
+)
+
+// Route restricts requests which match it to nodes which have all of its tags
+type Route struct {
+	// Methods are JSON-RPC methods which match the route, e.g. debug_traceTransaction
+	Methods []string
+	// PathPattern is a path.Match pattern which matches url path of requests, e.g. /debug/*
+	PathPattern string
+	// Tags are capabilities which a node must have to serve requests which match the route, e.g. archive
+	Tags []string
+}
+
+// matches reports whether the request matches the route, a batch matches if any of its calls does
+func (r Route) matches(request *http.Request, methods []string) bool {
+	for _, method := range methods {
+		if slices.Contains(r.Methods, method) {
+			return true
+		}
+	}
+
+	if r.PathPattern == "" {
+		return false
+	}
+
+	matched, _ := path.Match(r.PathPattern, request.URL.Path)
+	return matched
+}
+
+// requiredTags returns tags which a node must have to serve the request, it returns nil if the request matches no route
+func (c *Chain) requiredTags(request *http.Request, body []byte) map[string]bool {
+	if len(c.routes) == 0 {
+		return nil
+	}
+
+	methods := jsonRpcMethods(body)
+	var tags map[string]bool
+	for _, route := range c.routes {
+		if !route.matches(request, methods) {
+			continue
+		}
+
+		if tags == nil {
+			tags = make(map[string]bool)
+		}
+		for _, tag := range route.Tags {
+			tags[tag] = true
+		}
+	}
+
+	return tags
+}
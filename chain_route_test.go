@@ -0,0 +1,156 @@
+package eznode
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoutes(t *testing.T) {
+	t.Parallel()
+
+	mockedApiCall := contextApiCall(func(ctx context.Context, request *http.Request) (*Response, error) {
+		return &Response{
+			StatusCode: http.StatusOK,
+			Body:       []byte(request.URL.Host),
+			Headers:    &http.Header{},
+		}, nil
+	})
+
+	archiveNode := NewChainNode(NewChainNodeConfig{
+		Name: "Archive Node",
+		Url:  "http://archive.com",
+		Limit: ChainNodeLimit{
+			Count: 10,
+			Per:   1 * time.Minute,
+		},
+		RequestTimeout: 1 * time.Second,
+		Priority:       1,
+		Tags:           []string{"archive", "trace"},
+	})
+
+	fullNode := NewChainNode(NewChainNodeConfig{
+		Name: "Full Node",
+		Url:  "http://full.com",
+		Limit: ChainNodeLimit{
+			Count: 10,
+			Per:   1 * time.Minute,
+		},
+		RequestTimeout: 1 * time.Second,
+		Priority:       2,
+	})
+
+	createdChain := NewChain(
+		NewChainConfig{
+			Id: "test-chain",
+			Nodes: []*ChainNode{
+				archiveNode,
+				fullNode,
+			},
+			CheckTickRate: CheckTick{
+				TickRate:         100 * time.Millisecond,
+				MaxCheckDuration: 200 * time.Millisecond,
+			},
+			Routes: []Route{
+				{
+					Methods: []string{"debug_traceTransaction", "trace_block"},
+					Tags:    []string{"trace"},
+				},
+				{
+					PathPattern: "/archive/*",
+					Tags:        []string{"archive"},
+				},
+			},
+		},
+	)
+
+	ezNode := NewEzNode([]*Chain{createdChain}, WithApiClient(mockedApiCall))
+	send := func(path string, body string) string {
+		request, _ := http.NewRequest("POST", path, bytes.NewBufferString(body))
+		res, err := ezNode.SendRequest(context.Background(), "test-chain", request)
+		assert.Nil(t, err)
+		return string(res.Body)
+	}
+
+	assert.Equal(t, "full.com", send("", `{"jsonrpc":"2.0","id":1,"method":"eth_blockNumber"}`))
+	assert.Equal(t, "archive.com", send("", `{"jsonrpc":"2.0","id":1,"method":"debug_traceTransaction"}`))
+	assert.Equal(t, "archive.com", send("", `[{"id":1,"method":"eth_blockNumber"},{"id":2,"method":"trace_block"}]`))
+	assert.Equal(t, "archive.com", send("/archive/block", ``))
+	assert.Equal(t, "full.com", send("/block", ``))
+}
+
+func TestRouteWithoutMatchingNode(t *testing.T) {
+	t.Parallel()
+
+	mockedApiCall := contextApiCall(func(ctx context.Context, request *http.Request) (*Response, error) {
+		return &Response{
+			StatusCode: http.StatusOK,
+			Body:       []byte(request.URL.Host),
+			Headers:    &http.Header{},
+		}, nil
+	})
+
+	archiveNode := NewChainNode(NewChainNodeConfig{
+		Name: "Archive Node",
+		Url:  "http://archive.com",
+		Limit: ChainNodeLimit{
+			Count: 10,
+			Per:   1 * time.Minute,
+		},
+		RequestTimeout: 1 * time.Second,
+		Priority:       1,
+		Tags:           []string{"archive"},
+	})
+
+	traceNode := NewChainNode(NewChainNodeConfig{
+		Name: "Trace Node",
+		Url:  "http://trace.com",
+		Limit: ChainNodeLimit{
+			Count: 10,
+			Per:   1 * time.Minute,
+		},
+		RequestTimeout: 1 * time.Second,
+		Priority:       1,
+		Tags:           []string{"trace"},
+	})
+
+	createdChain := NewChain(
+		NewChainConfig{
+			Id: "test-chain",
+			Nodes: []*ChainNode{
+				archiveNode,
+				traceNode,
+			},
+			CheckTickRate: CheckTick{
+				TickRate:         100 * time.Millisecond,
+				MaxCheckDuration: 1 * time.Second,
+			},
+			Routes: []Route{
+				{
+					Methods: []string{"trace_block"},
+					Tags:    []string{"trace"},
+				},
+				{
+					PathPattern: "/archive/*",
+					Tags:        []string{"archive"},
+				},
+			},
+		},
+	)
+
+	ezNode := NewEzNode([]*Chain{createdChain}, WithApiClient(mockedApiCall))
+	request, _ := http.NewRequest("POST", "/archive/rpc", bytes.NewBufferString(`{"jsonrpc":"2.0","id":1,"method":"trace_block"}`))
+
+	startTime := time.Now()
+	_, err := ezNode.SendRequest(context.Background(), "test-chain", request)
+	assert.Less(t, time.Since(startTime), 100*time.Millisecond, "should not wait for a node which can never serve the request")
+
+	var ezNodeError EzNodeError
+	assert.ErrorAs(t, err, &ezNodeError)
+	assert.Equal(t, ErrorKindNoMatchingNode, ezNodeError.Kind)
+	assert.ErrorIs(t, err, errNoMatchingNode)
+}
@@ -1,6 +1,8 @@
 package eznode
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"testing"
 	"time"
@@ -40,7 +42,7 @@ func TestFindFreeNode(t *testing.T) {
 		},
 	)
 
-	foundNode := createdChain.getFreeNode(make(map[string]bool), make(map[string]bool))
+	foundNode, _ := createdChain.getFreeNode(context.Background(), nodeQuery{})
 
 	assert.NotNil(t, foundNode, "should find node")
 	if foundNode != nil {
@@ -80,9 +82,9 @@ func TestNotFindNode(t *testing.T) {
 		},
 	)
 
-	chainNode1.hits = 10
+	chainNode1.limiters[0].take(time.Now(), 10)
 
-	foundNode := createdChain.getFreeNode(make(map[string]bool), make(map[string]bool))
+	foundNode, _ := createdChain.getFreeNode(context.Background(), nodeQuery{})
 
 	assert.Nil(t, foundNode, "should not find node")
 }
@@ -119,12 +121,12 @@ func TestDisableNode(t *testing.T) {
 	)
 
 	createdChain.disableNode("Node 1")
-	foundNode := createdChain.getFreeNode(make(map[string]bool), make(map[string]bool))
+	foundNode, _ := createdChain.getFreeNode(context.Background(), nodeQuery{})
 
 	assert.Nil(t, foundNode, "should not find node")
 
 	createdChain.enableNode("Node 1")
-	foundNode = createdChain.getFreeNode(make(map[string]bool), make(map[string]bool))
+	foundNode, _ = createdChain.getFreeNode(context.Background(), nodeQuery{})
 
 	assert.NotNil(t, foundNode, "should not find node")
 }
@@ -161,15 +163,15 @@ func TestDisableNodeWithTime(t *testing.T) {
 	)
 
 	createdChain.disableNodeWithTime("Node 1", 2*time.Second)
-	foundNode := createdChain.getFreeNode(make(map[string]bool), make(map[string]bool))
+	foundNode, _ := createdChain.getFreeNode(context.Background(), nodeQuery{})
 	assert.Nil(t, foundNode, "should not find node")
 
 	time.Sleep(1 * time.Second)
-	foundNode = createdChain.getFreeNode(make(map[string]bool), make(map[string]bool))
+	foundNode, _ = createdChain.getFreeNode(context.Background(), nodeQuery{})
 	assert.Nil(t, foundNode, "should not find node")
 
 	time.Sleep(1 * time.Second)
-	foundNode = createdChain.getFreeNode(make(map[string]bool), make(map[string]bool))
+	foundNode, _ = createdChain.getFreeNode(context.Background(), nodeQuery{})
 	assert.NotNil(t, foundNode, "should find node")
 }
 
@@ -220,13 +222,13 @@ func TestLoadBalance(t *testing.T) {
 		},
 	)
 
-	chainNode1.hits = 1
-	foundNode := createdChain.getFreeNode(make(map[string]bool), make(map[string]bool))
+	chainNode1.limiters[0].take(time.Now(), 1)
+	foundNode, _ := createdChain.getFreeNode(context.Background(), nodeQuery{})
 	assert.Equal(t, chainNode2.name, foundNode.name, "should route to node 2")
 
-	chainNode2.hits = 3
-	chainNode1.hits = 2
-	foundNode = createdChain.getFreeNode(make(map[string]bool), make(map[string]bool))
+	chainNode2.limiters[0].take(time.Now(), 2)
+	chainNode1.limiters[0].take(time.Now(), 1)
+	foundNode, _ = createdChain.getFreeNode(context.Background(), nodeQuery{})
 	assert.Equal(t, chainNode1.name, foundNode.name, "should route to node 1")
 }
 
@@ -280,15 +282,125 @@ func TestShouldFilterNode(t *testing.T) {
 	includeNodes := make(map[string]bool)
 	includeNodes[chainNode1.name] = true
 
-	foundNode := createdChain.getFreeNode(make(map[string]bool), includeNodes)
+	foundNode, _ := createdChain.getFreeNode(context.Background(), nodeQuery{includeNodes: includeNodes})
 	assert.Equal(t, chainNode1.name, foundNode.name)
-	foundNode = createdChain.getFreeNode(make(map[string]bool), includeNodes)
+	foundNode, _ = createdChain.getFreeNode(context.Background(), nodeQuery{includeNodes: includeNodes})
 	assert.Equal(t, chainNode1.name, foundNode.name)
-	foundNode = createdChain.getFreeNode(make(map[string]bool), includeNodes)
+	foundNode, _ = createdChain.getFreeNode(context.Background(), nodeQuery{includeNodes: includeNodes})
 	assert.Equal(t, chainNode1.name, foundNode.name)
 
-	foundNode = createdChain.getFreeNode(make(map[string]bool), includeNodes)
+	foundNode, _ = createdChain.getFreeNode(context.Background(), nodeQuery{includeNodes: includeNodes})
 	assert.Nil(t, foundNode)
-	foundNode = createdChain.getFreeNode(make(map[string]bool), includeNodes)
+	foundNode, _ = createdChain.getFreeNode(context.Background(), nodeQuery{includeNodes: includeNodes})
 	assert.Nil(t, foundNode)
 }
+
+func TestStackedLimits(t *testing.T) {
+	t.Parallel()
+
+	chainNode1 := NewChainNode(NewChainNodeConfig{
+		Name: "Node 1",
+		Url:  "http://example.com",
+		Limit: ChainNodeLimit{
+			Count: 10,
+			Per:   1 * time.Second,
+		},
+		Limits: []ChainNodeLimit{
+			{
+				Count: 2,
+				Per:   1 * time.Hour,
+			},
+		},
+		RequestTimeout: 1 * time.Second,
+		Priority:       1,
+	})
+
+	createdChain := NewChain(
+		NewChainConfig{
+			Id: "test chain",
+			Nodes: []*ChainNode{
+				chainNode1,
+			},
+			CheckTickRate: CheckTick{
+				TickRate:         100 * time.Millisecond,
+				MaxCheckDuration: 200 * time.Millisecond,
+			},
+			RetryCount: 2,
+		},
+	)
+
+	foundNode, _ := createdChain.getFreeNode(context.Background(), nodeQuery{})
+	assert.NotNil(t, foundNode)
+	foundNode, _ = createdChain.getFreeNode(context.Background(), nodeQuery{})
+	assert.NotNil(t, foundNode)
+	foundNode, _ = createdChain.getFreeNode(context.Background(), nodeQuery{})
+	assert.Nil(t, foundNode, "should respect every limit of the node")
+
+	limitStats := createdChain.getStats()[0].LimitStats
+	assert.Equal(t, 2, len(limitStats))
+	assert.Equal(t, uint(2), limitStats[1].Used)
+	assert.Equal(t, uint(2), limitStats[1].Count)
+}
+
+func TestWaitForFreeNodeRespectsContext(t *testing.T) {
+	t.Parallel()
+
+	mockedApiCall := mockApiCall{
+		returnFunc: func(request *http.Request) (*Response, error) {
+			return &Response{
+				StatusCode: 200,
+				Headers:    &http.Header{},
+			}, nil
+		},
+		validateFunc: func(request *http.Request) {
+		},
+	}
+
+	chainNode1 := NewChainNode(NewChainNodeConfig{
+		Name: "Node 1",
+		Url:  "http://example.com",
+		Limit: ChainNodeLimit{
+			Count: 1,
+			Per:   1 * time.Minute,
+		},
+		RequestTimeout: 1 * time.Second,
+		Priority:       1,
+	})
+
+	createdChain := NewChain(
+		NewChainConfig{
+			Id: "test-chain",
+			Nodes: []*ChainNode{
+				chainNode1,
+			},
+			CheckTickRate: CheckTick{
+				TickRate:         100 * time.Millisecond,
+				MaxCheckDuration: 10 * time.Second,
+			},
+			RetryCount: 2,
+		},
+	)
+
+	ezNode := NewEzNode([]*Chain{createdChain}, WithApiClient(mockedApiCall))
+	request, _ := http.NewRequest("GET", "/", nil)
+	_, err := ezNode.SendRequest(context.Background(), "test-chain", request)
+	assert.Nil(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	startTime := time.Now()
+	_, err = ezNode.SendRequest(ctx, "test-chain", request)
+	assert.Less(t, time.Since(startTime), 1*time.Second)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	var ezNodeError EzNodeError
+	assert.True(t, errors.As(err, &ezNodeError))
+	assert.Equal(t, ErrorKindCanceled, ezNodeError.Kind)
+	assert.Equal(t, "test-chain", ezNodeError.Metadata.ChainId)
+
+	startTime = time.Now()
+	_, err = ezNode.SendRequest(context.Background(), "test-chain", request, WithMaxWait(100*time.Millisecond))
+	assert.Less(t, time.Since(startTime), 1*time.Second)
+	assert.True(t, errors.As(err, &ezNodeError))
+	assert.Equal(t, ErrorKindFullCapacity, ezNodeError.Kind)
+}
@@ -0,0 +1,129 @@
+package eznode
+
+import (
+	"slices"
+	"time"
+)
+
+// waiter is a request which waits for a free node
+type waiter struct {
+	query nodeQuery
+	node  chan *ChainNode
+}
+
+// waitQueue is protected by the mutex of the chain which owns it
+type waitQueue struct {
+	waiters []*waiter
+	timer   *time.Timer
+}
+
+// add puts the waiter behind waiters of the same or a more important priority class
+func (q *waitQueue) add(w *waiter) {
+	index := len(q.waiters)
+	for index > 0 && q.waiters[index-1].query.class.rank() > w.query.class.rank() {
+		index -= 1
+	}
+
+	q.waiters = slices.Insert(q.waiters, index, w)
+}
+
+func (q *waitQueue) remove(w *waiter) bool {
+	for i, queued := range q.waiters {
+		if queued == w {
+			q.waiters = append(q.waiters[:i], q.waiters[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *Chain) queueLength() int {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return len(c.waitQueue.waiters)
+}
+
+// dispatch hands free nodes to waiters, it is called when capacity of the chain may have changed
+func (c *Chain) dispatch() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.dispatchLocked()
+}
+
+// dispatchLocked hands free nodes to waiters in priority class and arrival order, it must be called while the chain is locked
+// a waiter which cannot be served does not block later waiters which need other nodes,
+// but the node which the first waiter of a class waits for is held for it, so cheaper later waiters cannot take its capacity
+func (c *Chain) dispatchLocked() {
+	now := time.Now()
+	held := make(map[*ChainNode]bool)
+	waitingClasses := make(map[PriorityClass]bool)
+	remaining := c.waitQueue.waiters[:0]
+	for _, w := range c.waitQueue.waiters {
+		if foundNode := c.findNodeLocked(w.query, now, held); foundNode != nil {
+			w.node <- foundNode
+			continue
+		}
+
+		remaining = append(remaining, w)
+		if !waitingClasses[w.query.class] {
+			waitingClasses[w.query.class] = true
+			if heldNode := c.soonestNodeLocked(w.query, now, held); heldNode != nil {
+				held[heldNode] = true
+			}
+		}
+	}
+	clear(c.waitQueue.waiters[len(remaining):])
+	c.waitQueue.waiters = remaining
+
+	if len(remaining) > 0 {
+		c.scheduleDispatchLocked(now)
+	}
+}
+
+// soonestNodeLocked returns the node whose limits allow the query soonest, it is nil if no node can ever serve the query
+// it must be called while the chain is locked
+func (c *Chain) soonestNodeLocked(query nodeQuery, now time.Time, held map[*ChainNode]bool) *ChainNode {
+	cost := query.requestCost()
+	reserve := c.reserveFor(query.class)
+	var soonestNode *ChainNode
+	var soonestWait time.Duration
+	for _, node := range c.nodes {
+		if held[node] || !c.canServeLocked(node, query, now) {
+			continue
+		}
+
+		wait := node.timeUntilAllowed(now, cost, reserve)
+		if wait >= 0 && (soonestNode == nil || wait < soonestWait) {
+			soonestNode = node
+			soonestWait = wait
+		}
+	}
+
+	return soonestNode
+}
+
+// scheduleDispatchLocked wakes the queue up when a node limit is expected to have capacity again
+// other changes, such as an enabled node, are picked up at most after TickRate
+func (c *Chain) scheduleDispatchLocked(now time.Time) {
+	minCost := c.waitQueue.waiters[0].query.requestCost()
+	for _, w := range c.waitQueue.waiters {
+		minCost = min(minCost, w.query.requestCost())
+	}
+
+	delay := c.checkTickRate.TickRate
+	for _, node := range c.nodes {
+		if nodeDelay := node.timeUntilAllowed(now, minCost, 0); nodeDelay > 0 && nodeDelay < delay {
+			delay = nodeDelay
+		}
+	}
+
+	if c.waitQueue.timer == nil {
+		c.waitQueue.timer = time.AfterFunc(delay, c.dispatch)
+		return
+	}
+
+	c.waitQueue.timer.Reset(delay)
+}
@@ -0,0 +1,188 @@
+package eznode
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWaitQueueServesInArrivalOrder(t *testing.T) {
+	t.Parallel()
+
+	chainNode1 := NewChainNode(NewChainNodeConfig{
+		Name: "Node 1",
+		Url:  "http://example.com",
+		Limit: ChainNodeLimit{
+			Count: 1,
+			Per:   100 * time.Millisecond,
+		},
+		RequestTimeout: 1 * time.Second,
+		Priority:       1,
+	})
+
+	createdChain := NewChain(
+		NewChainConfig{
+			Id: "test chain",
+			Nodes: []*ChainNode{
+				chainNode1,
+			},
+			CheckTickRate: CheckTick{
+				TickRate:         1 * time.Second,
+				MaxCheckDuration: 2 * time.Second,
+			},
+			RetryCount: 2,
+		},
+	)
+
+	foundNode, _ := createdChain.getFreeNode(context.Background(), nodeQuery{})
+	assert.NotNil(t, foundNode)
+
+	mutex := &sync.Mutex{}
+	order := make([]int, 0)
+	w := &sync.WaitGroup{}
+	for i := 0; i < 3; i++ {
+		w.Add(1)
+		go func(i int) {
+			defer w.Done()
+			foundNode, err := createdChain.getFreeNode(context.Background(), nodeQuery{})
+			assert.Nil(t, err)
+			assert.NotNil(t, foundNode)
+
+			mutex.Lock()
+			order = append(order, i)
+			mutex.Unlock()
+		}(i)
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, 3, createdChain.queueLength())
+
+	startTime := time.Now()
+	w.Wait()
+	assert.Equal(t, []int{0, 1, 2}, order)
+	assert.Less(t, time.Since(startTime), 1*time.Second, "should wake up when limit refills, not on tick")
+}
+
+func TestWaitQueueShedsLoad(t *testing.T) {
+	t.Parallel()
+
+	mockedApiCall := mockApiCall{
+		returnFunc: func(request *http.Request) (*Response, error) {
+			return &Response{
+				StatusCode: 200,
+				Headers:    &http.Header{},
+			}, nil
+		},
+		validateFunc: func(request *http.Request) {
+		},
+	}
+
+	chainNode1 := NewChainNode(NewChainNodeConfig{
+		Name: "Node 1",
+		Url:  "http://example.com",
+		Limit: ChainNodeLimit{
+			Count: 1,
+			Per:   1 * time.Minute,
+		},
+		RequestTimeout: 1 * time.Second,
+		Priority:       1,
+	})
+
+	createdChain := NewChain(
+		NewChainConfig{
+			Id: "test-chain",
+			Nodes: []*ChainNode{
+				chainNode1,
+			},
+			CheckTickRate: CheckTick{
+				TickRate:         100 * time.Millisecond,
+				MaxCheckDuration: 500 * time.Millisecond,
+			},
+			RetryCount:     2,
+			MaxQueueLength: 1,
+		},
+	)
+
+	ezNode := NewEzNode([]*Chain{createdChain}, WithApiClient(mockedApiCall))
+	request, _ := http.NewRequest("GET", "/", nil)
+	_, err := ezNode.SendRequest(context.Background(), "test-chain", request)
+	assert.Nil(t, err)
+
+	go ezNode.SendRequest(context.Background(), "test-chain", request)
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, 1, ezNode.GetStats()[0].QueueLength)
+
+	startTime := time.Now()
+	_, err = ezNode.SendRequest(context.Background(), "test-chain", request)
+	assert.Less(t, time.Since(startTime), 100*time.Millisecond, "should fail immediately")
+
+	var ezNodeError EzNodeError
+	assert.True(t, errors.As(err, &ezNodeError))
+	assert.Equal(t, ErrorKindQueueFull, ezNodeError.Kind)
+}
+
+func TestWaitQueueHoldsCapacityForHead(t *testing.T) {
+	t.Parallel()
+
+	chainNode1 := NewChainNode(NewChainNodeConfig{
+		Name: "Node 1",
+		Url:  "http://example.com",
+		Limit: ChainNodeLimit{
+			Count: 5,
+			Per:   500 * time.Millisecond,
+		},
+		RequestTimeout: 1 * time.Second,
+		Priority:       1,
+	})
+
+	createdChain := NewChain(
+		NewChainConfig{
+			Id: "test chain",
+			Nodes: []*ChainNode{
+				chainNode1,
+			},
+			CheckTickRate: CheckTick{
+				TickRate:         50 * time.Millisecond,
+				MaxCheckDuration: 1 * time.Second,
+			},
+		},
+	)
+
+	// the limit is released one request every 100 milliseconds
+	now := time.Now()
+	createdChain.mutex.Lock()
+	for i := 4; i >= 0; i-- {
+		chainNode1.limiters[0].take(now.Add(-time.Duration(i)*100*time.Millisecond), 1)
+	}
+	createdChain.mutex.Unlock()
+
+	mutex := &sync.Mutex{}
+	order := make([]string, 0)
+	w := &sync.WaitGroup{}
+	wait := func(name string, cost uint) {
+		defer w.Done()
+		foundNode, _ := createdChain.getFreeNode(context.Background(), nodeQuery{cost: cost})
+		if foundNode != nil {
+			mutex.Lock()
+			order = append(order, name)
+			mutex.Unlock()
+		}
+	}
+
+	w.Add(1)
+	go wait("expensive", 5)
+	time.Sleep(10 * time.Millisecond)
+	for i := 0; i < 5; i++ {
+		w.Add(1)
+		go wait("cheap", 1)
+		time.Sleep(10 * time.Millisecond)
+	}
+	w.Wait()
+
+	assert.NotEmpty(t, order)
+	assert.Equal(t, "expensive", order[0], "released capacity should be held for the first waiter")
+}
@@ -0,0 +1,29 @@
+package eznode
+
+import "net/http"
+
+// CostFunc determines how much of the node limits a request consumes, e.g. compute units of an RPC method
+// body is the request body, request.Body must not be read
+type CostFunc func(request *http.Request, body []byte) uint
+
+// JsonRpcMethodCost creates a CostFunc which looks up the cost of JSON-RPC methods in costs
+// methods which are not in costs cost defaultCost, a batch costs the sum of its calls
+func JsonRpcMethodCost(costs map[string]uint, defaultCost uint) CostFunc {
+	return func(request *http.Request, body []byte) uint {
+		methods := jsonRpcMethods(body)
+		if len(methods) == 0 {
+			return defaultCost
+		}
+
+		totalCost := uint(0)
+		for _, method := range methods {
+			cost, ok := costs[method]
+			if !ok {
+				cost = defaultCost
+			}
+			totalCost += cost
+		}
+
+		return totalCost
+	}
+}
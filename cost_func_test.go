@@ -0,0 +1,74 @@
+package eznode
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestCost(t *testing.T) {
+	t.Parallel()
+
+	mockedApiCall := mockApiCall{
+		returnFunc: func(request *http.Request) (*Response, error) {
+			return &Response{
+				StatusCode: 200,
+				Headers:    &http.Header{},
+			}, nil
+		},
+		validateFunc: func(request *http.Request) {
+		},
+	}
+
+	chainNode1 := NewChainNode(NewChainNodeConfig{
+		Name: "Node 1",
+		Url:  "http://example.com",
+		Limit: ChainNodeLimit{
+			Count: 100,
+			Per:   1 * time.Hour,
+		},
+		RequestTimeout: 1 * time.Second,
+		Priority:       1,
+	})
+
+	createdChain := NewChain(
+		NewChainConfig{
+			Id: "test-chain",
+			Nodes: []*ChainNode{
+				chainNode1,
+			},
+			CheckTickRate: CheckTick{
+				TickRate:         100 * time.Millisecond,
+				MaxCheckDuration: 200 * time.Millisecond,
+			},
+			RetryCount: 2,
+			CostFunc: JsonRpcMethodCost(map[string]uint{
+				"eth_getLogs": 75,
+			}, 1),
+		},
+	)
+
+	ezNode := NewEzNode([]*Chain{createdChain}, WithApiClient(mockedApiCall))
+
+	request, _ := http.NewRequest("POST", "/", bytes.NewBufferString(`{"jsonrpc":"2.0","id":1,"method":"eth_chainId"}`))
+	_, err := ezNode.SendRequest(context.Background(), "test-chain", request)
+	assert.Nil(t, err)
+	assert.Equal(t, uint(1), chainNode1.hits())
+
+	request, _ = http.NewRequest("POST", "/", bytes.NewBufferString(`{"jsonrpc":"2.0","id":1,"method":"eth_getLogs"}`))
+	_, err = ezNode.SendRequest(context.Background(), "test-chain", request)
+	assert.Nil(t, err)
+	assert.Equal(t, uint(76), chainNode1.hits())
+
+	request, _ = http.NewRequest("GET", "/", nil)
+	_, err = ezNode.SendRequest(context.Background(), "test-chain", request, WithCost(30))
+	assert.NotNil(t, err, "should not exceed node limit")
+
+	_, err = ezNode.SendRequest(context.Background(), "test-chain", request, WithCost(24))
+	assert.Nil(t, err)
+	assert.Equal(t, uint(100), chainNode1.hits())
+}
@@ -1,13 +1,70 @@
 package eznode
 
-import "fmt"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// ErrorKind tells why EzNode could not respond a request
+type ErrorKind int
+
+const (
+	// ErrorKindUnknown is the kind of errors which are not categorized
+	ErrorKindUnknown ErrorKind = iota
+	// ErrorKindFullCapacity means no node of the chain was free to send the request to
+	ErrorKindFullCapacity
+	// ErrorKindMaxRetries means the request failed on all attempts
+	ErrorKindMaxRetries
+	// ErrorKindRetryBudgetExhausted means the request failed and the chain retry budget did not allow a retry
+	ErrorKindRetryBudgetExhausted
+	// ErrorKindCanceled means the context of the request was cancelled or its deadline passed
+	ErrorKindCanceled
+	// ErrorKindQueueFull means too many requests were waiting for a free node, so the request was shed
+	ErrorKindQueueFull
+	// ErrorKindQuorumNotReached means not enough nodes agreed on the response of a quorum request
+	ErrorKindQuorumNotReached
+	// ErrorKindBodyTooLarge means the response body is larger than MaxBodySize of the chain
+	ErrorKindBodyTooLarge
+	// ErrorKindNoMatchingNode means no node of the chain has the tags which routes of the chain require for the request
+	ErrorKindNoMatchingNode
+)
+
+func (k ErrorKind) String() string {
+	switch k {
+	case ErrorKindFullCapacity:
+		return "full capacity"
+	case ErrorKindMaxRetries:
+		return "max retries"
+	case ErrorKindRetryBudgetExhausted:
+		return "retry budget exhausted"
+	case ErrorKindCanceled:
+		return "canceled"
+	case ErrorKindQueueFull:
+		return "queue full"
+	case ErrorKindQuorumNotReached:
+		return "quorum not reached"
+	case ErrorKindBodyTooLarge:
+		return "body too large"
+	case ErrorKindNoMatchingNode:
+		return "no matching node"
+	default:
+		return "unknown"
+	}
+}
 
 // EzNodeError is the error type for EzNode
 type EzNodeError struct {
 	// Message is the error message
 	Message string
+	// Kind is the category of the error
+	Kind ErrorKind
 	// Metadata is the error metadata
 	Metadata ChainResponseMetadata
+	// Err is the underlying error, e.g. context.Canceled
+	Err error
 }
 
 func (e EzNodeError) Error() string {
@@ -21,3 +78,46 @@ func (e EzNodeError) Error() string {
 
 	return e.Message
 }
+
+func (e EzNodeError) Unwrap() error {
+	return e.Err
+}
+
+// statusClientClosedRequest is the conventional status code of requests which the client cancelled
+const statusClientClosedRequest = 499
+
+func newCanceledError(err error, metadata ChainResponseMetadata) EzNodeError {
+	statusCode := statusClientClosedRequest
+	if errors.Is(err, context.DeadlineExceeded) {
+		statusCode = http.StatusRequestTimeout
+	}
+
+	metadata.Trace = append(metadata.Trace, NodeTrace{
+		Time:       time.Now(),
+		StatusCode: statusCode,
+		Err:        err,
+	})
+
+	return EzNodeError{
+		Message:  fmt.Sprintf("'%s' chain request canceled: %v", metadata.ChainId, err),
+		Kind:     ErrorKindCanceled,
+		Metadata: metadata,
+		Err:      err,
+	}
+}
+
+func newNoMatchingNodeError(metadata ChainResponseMetadata) EzNodeError {
+	errorMessage := fmt.Sprintf("'%s' chain has no node with the tags which its routes require for the request", metadata.ChainId)
+	metadata.Trace = append(metadata.Trace, NodeTrace{
+		Time:       time.Now(),
+		StatusCode: http.StatusNotImplemented,
+		Err:        errors.New(errorMessage),
+	})
+
+	return EzNodeError{
+		Message:  errorMessage,
+		Kind:     ErrorKindNoMatchingNode,
+		Metadata: metadata,
+		Err:      errNoMatchingNode,
+	}
+}
@@ -1,7 +1,6 @@
 package eznode
 
 import (
-	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -13,21 +12,25 @@ import (
 )
 
 type EzNode struct {
-	chains      map[string]*Chain
-	apiCaller   ApiCaller
-	syncStorage syncStorage
+	chains       map[string]*Chain
+	apiCaller    ApiCaller
+	syncStorage  syncStorage
+	jsonRpcId    uint64
+	cache        Cache
+	coalescing   *coalescing
+	optionErrors fieldErrors
 }
 
-func generateTrace(nodeName string, err error, resStatus int) NodeTrace {
+func generateTrace(nodeName string, err error, resStatus int, duration time.Duration) NodeTrace {
 	nodeTrace := NodeTrace{
 		Time:     time.Now(),
 		NodeName: nodeName,
+		Duration: duration,
 	}
 
 	if err != nil {
 		nodeTrace.Err = err
-		netError, ok := err.(net.Error)
-		if errors.Is(err, context.DeadlineExceeded) || (ok && netError.Timeout()) {
+		if isTimeoutError(err) {
 			nodeTrace.StatusCode = http.StatusRequestTimeout
 			return nodeTrace
 		}
@@ -44,14 +47,20 @@ func generateTrace(nodeName string, err error, resStatus int) NodeTrace {
 // SendRequest send your request to specific chain
 // If chain not found, return error
 // Note: make sure which your request should not have host, schema and port
-func (e *EzNode) SendRequest(ctx context.Context, chainId string, request *http.Request) (*Response, error) {
-	return e.SendRequestSpecific(ctx, chainId, request, []string{})
+func (e *EzNode) SendRequest(ctx context.Context, chainId string, request *http.Request, options ...RequestOption) (*Response, error) {
+	return e.SendRequestSpecific(ctx, chainId, request, []string{}, options...)
 }
 
 // SendRequestSpecific send your request with specifying which node you want to use
 // if your node rely on specific node (usually node which has more history) you can use
 // this function to ensure your request will be responded by this node
-func (e *EzNode) SendRequestSpecific(ctx context.Context, chainId string, request *http.Request, includeNodeList []string) (*Response, error) {
+func (e *EzNode) SendRequestSpecific(
+	ctx context.Context,
+	chainId string,
+	request *http.Request,
+	includeNodeList []string,
+	options ...RequestOption,
+) (*Response, error) {
 	selectedChain := e.chains[chainId]
 	if selectedChain == nil {
 		return nil, errors.New(fmt.Sprintf("cannot find chain id %s", chainId))
@@ -62,9 +71,6 @@ func (e *EzNode) SendRequestSpecific(ctx context.Context, chainId string, reques
 		includeNodes[includeNode] = true
 	}
 
-	excludeNodes := make(map[string]bool)
-	nodeTrace := make([]NodeTrace, 0)
-
 	var reqBody []byte
 	var err error
 	if request.Body != nil {
@@ -74,13 +80,158 @@ func (e *EzNode) SendRequestSpecific(ctx context.Context, chainId string, reques
 		}
 	}
 
+	cacheKey := ""
+	cacheTTL, cacheable := selectedChain.cacheTTL(request, reqBody)
+	if e.cache != nil && cacheable {
+		cacheKey = withIncludeNodes(requestKey(selectedChain.id, request, reqBody), includeNodeList)
+		if res, ok := e.cache.Get(cacheKey); ok {
+			atomic.AddUint64(&selectedChain.cacheHits, 1)
+			if call, ok := parseJsonRpcCall(reqBody); ok {
+				res.Body = withJsonRpcId(res.Body, call.Id)
+			}
+			res.Metadata = ChainResponseMetadata{
+				ChainId:      selectedChain.id,
+				RequestedUrl: request.URL.String(),
+				Trace:        make([]NodeTrace, 0),
+				Cached:       true,
+			}
+			return res, nil
+		}
+		atomic.AddUint64(&selectedChain.cacheMisses, 1)
+	}
+
+	requestOpts := newRequestOptions(options)
+	send := func(ctx context.Context) (*Response, error) {
+		result, err := e.sendRequest(ctx, selectedChain, request, reqBody, includeNodes, requestOpts)
+		if err != nil {
+			return nil, err
+		}
+
+		if cacheKey != "" && isResponseCacheable(result.res) {
+			e.cache.Set(cacheKey, result.res, cacheTTL)
+		}
+
+		return result.res, nil
+	}
+
+	if requestOpts.coalesce {
+		return e.coalescing.do(
+			ctx,
+			coalescingKey(selectedChain.id, request, reqBody, includeNodeList, requestOpts),
+			reqBody,
+			ChainResponseMetadata{
+				ChainId:      selectedChain.id,
+				RequestedUrl: request.URL.String(),
+			},
+			send,
+		)
+	}
+
+	return send(ctx)
+}
+
+// sendRequest sends the request to free nodes of the chain until a node responds or retries run out
+// it returns the valid result with its metadata set
+func (e *EzNode) sendRequest(
+	ctx context.Context,
+	selectedChain *Chain,
+	request *http.Request,
+	reqBody []byte,
+	includeNodes map[string]bool,
+	requestOpts *requestOptions,
+) (*attemptResult, error) {
+	excludeNodes := make(map[string]bool)
+	nodeTrace := make([]NodeTrace, 0)
+	query := nodeQuery{
+		excludeNodes: excludeNodes,
+		includeNodes: includeNodes,
+		cost:         selectedChain.requestCost(requestOpts, request, reqBody),
+		maxWait:      requestOpts.maxWait,
+		class:        requestOpts.class,
+		requiredTags: selectedChain.requiredTags(request, reqBody),
+	}
+
+	maxRetries := selectedChain.maxRetries(includeNodes, query.requiredTags)
 	tryCount := 0
-	for tryCount < selectedChain.retryCount {
-		selectedNode := selectedChain.getFreeNode(excludeNodes, includeNodes)
-		if selectedNode == nil {
+	for tryCount <= maxRetries {
+		if selectedChain.retryBudget != nil {
+			if tryCount == 0 {
+				selectedChain.retryBudget.recordAttempt(time.Now())
+			} else if !selectedChain.retryBudget.tryRetry(time.Now()) {
+				errorMessage := fmt.Sprintf("'%s' chain retry budget is exhausted", selectedChain.id)
+				return nil, EzNodeError{
+					Message: errorMessage,
+					Kind:    ErrorKindRetryBudgetExhausted,
+					Metadata: ChainResponseMetadata{
+						ChainId:      selectedChain.id,
+						RequestedUrl: request.URL.String(),
+						Retry:        tryCount - 1,
+						Trace: append(nodeTrace, NodeTrace{
+							Time:       time.Now(),
+							StatusCode: http.StatusServiceUnavailable,
+							Err:        errors.New(errorMessage),
+						}),
+					},
+				}
+			}
+		}
+
+		waited := time.Duration(0)
+		if tryCount > 0 && selectedChain.retryPolicy != nil {
+			waited = selectedChain.retryPolicy.delay(tryCount)
+			if err := sleepContext(ctx, waited); err != nil {
+				return nil, newCanceledError(err, ChainResponseMetadata{
+					ChainId:      selectedChain.id,
+					RequestedUrl: request.URL.String(),
+					Retry:        tryCount,
+					Trace:        nodeTrace,
+				})
+			}
+		}
+
+		selectedNode, err := selectedChain.getFreeNode(ctx, query)
+		if err != nil && ctx.Err() != nil {
+			return nil, newCanceledError(err, ChainResponseMetadata{
+				ChainId:      selectedChain.id,
+				RequestedUrl: request.URL.String(),
+				Retry:        tryCount,
+				Trace:        nodeTrace,
+			})
+		}
+
+		if errors.Is(err, errQueueFull) {
+			errorMessage := fmt.Sprintf("'%s' chain wait queue is full", selectedChain.id)
+			return nil, EzNodeError{
+				Message: errorMessage,
+				Kind:    ErrorKindQueueFull,
+				Metadata: ChainResponseMetadata{
+					ChainId:      selectedChain.id,
+					RequestedUrl: request.URL.String(),
+					Retry:        tryCount,
+					Trace: append(nodeTrace, NodeTrace{
+						Time:       time.Now(),
+						StatusCode: http.StatusServiceUnavailable,
+						Err:        errors.New(errorMessage),
+						Wait:       waited,
+					}),
+				},
+			}
+		}
+
+		if errors.Is(err, errNoMatchingNode) {
+			return nil, newNoMatchingNodeError(ChainResponseMetadata{
+				ChainId:      selectedChain.id,
+				RequestedUrl: request.URL.String(),
+				Retry:        tryCount,
+				Trace:        nodeTrace,
+			})
+		}
+
+		if err != nil {
 			errorMessage := fmt.Sprintf("'%s' chain is at full capacity", selectedChain.id)
 			return nil, EzNodeError{
 				Message: errorMessage,
+				Kind:    ErrorKindFullCapacity,
 				Metadata: ChainResponseMetadata{
 					ChainId:      selectedChain.id,
 					RequestedUrl: request.URL.String(),
@@ -89,43 +240,74 @@ func (e *EzNode) SendRequestSpecific(ctx context.Context, chainId string, reques
 						Time:       time.Now(),
 						StatusCode: http.StatusTooManyRequests,
 						Err:        errors.New(errorMessage),
+						Wait:       waited,
 					}),
 				},
 			}
 		}
 
-		clonedReq := request.Clone(context.Background())
-		clonedReq.Body = io.NopCloser(bytes.NewBuffer(reqBody))
-		clonedReq = selectedNode.middleware(clonedReq)
-		ctxTimeout, cancelTimeout := context.WithTimeout(ctx, selectedNode.requestTimeout)
-		defer cancelTimeout()
+		var results []attemptResult
+		if requestOpts.stream {
+			results = []attemptResult{e.streamAttempt(ctx, selectedChain, selectedNode, request, reqBody)}
+		} else if requestOpts.hedge != nil {
+			results = e.hedgedAttempt(ctx, selectedChain, selectedNode, query, request, reqBody, requestOpts.hedge)
+		} else {
+			results = []attemptResult{e.attempt(ctx, selectedChain, selectedNode, request, reqBody)}
+		}
+		results[0].trace.Wait = waited
 
-		res, err := e.apiCaller.DoRequest(ctxTimeout, clonedReq)
-		isValid := isResponseValid(selectedChain.failureStatusCodes, res, err)
-		go releaseResource(selectedChain, selectedNode)
-		go collectMetric(selectedNode, res, err, isValid)
-		if isValid {
-			res.Metadata = ChainResponseMetadata{
+		var validResult *attemptResult
+		for i := range results {
+			nodeTrace = append(nodeTrace, results[i].trace)
+			excludeNodes[results[i].node.name] = true
+			if results[i].isValid && validResult == nil {
+				validResult = &results[i]
+			}
+		}
+
+		if validResult != nil {
+			metadata := ChainResponseMetadata{
 				ChainId:      selectedChain.id,
 				RequestedUrl: request.URL.String(),
 				Retry:        tryCount,
-				Trace: append(nodeTrace, NodeTrace{
-					Time:       time.Now(),
-					NodeName:   selectedNode.name,
-					StatusCode: res.StatusCode,
-					Err:        nil,
-				}),
+				Trace:        nodeTrace,
+			}
+			if validResult.stream != nil {
+				validResult.stream.Metadata = metadata
+			} else {
+				validResult.res.Metadata = metadata
+			}
+			return validResult, nil
+		}
+
+		for i := range results {
+			// other nodes would respond the same body, so the request is not retried
+			if errors.Is(results[i].err, errBodyTooLarge) {
+				errorMessage := fmt.Sprintf("'%s' chain response body is larger than %d bytes", selectedChain.id, selectedChain.maxBodySize)
+				return nil, EzNodeError{
+					Message: errorMessage,
+					Kind:    ErrorKindBodyTooLarge,
+					Metadata: ChainResponseMetadata{
+						ChainId:      selectedChain.id,
+						RequestedUrl: request.URL.String(),
+						Retry:        tryCount,
+						Trace:        nodeTrace,
+					},
+					Err: errBodyTooLarge,
+				}
 			}
-			return res, nil
 		}
 
-		resStatusCode := 0
-		if res != nil {
-			resStatusCode = res.StatusCode
+		if ctx.Err() != nil {
+			return nil, newCanceledError(ctx.Err(), ChainResponseMetadata{
+				ChainId:      selectedChain.id,
+				RequestedUrl: request.URL.String(),
+				Retry:        tryCount,
+				Trace:        nodeTrace,
+			})
 		}
 
-		nodeTrace = append(nodeTrace, generateTrace(selectedNode.name, err, resStatusCode))
-		excludeNodes[selectedNode.name] = true
+		tryCount += 1
 	}
 
 	httpStatusCode := http.StatusFailedDependency
@@ -133,10 +315,11 @@ func (e *EzNode) SendRequestSpecific(ctx context.Context, chainId string, reques
 
 	return nil, EzNodeError{
 		Message: errorMessage,
+		Kind:    ErrorKindMaxRetries,
 		Metadata: ChainResponseMetadata{
 			ChainId:      selectedChain.id,
 			RequestedUrl: request.URL.String(),
-			Retry:        tryCount,
+			Retry:        maxRetries,
 			Trace: append(nodeTrace, NodeTrace{
 				StatusCode: httpStatusCode,
 				Err:        errors.New(errorMessage),
@@ -150,32 +333,72 @@ func collectMetric(
 	res *Response,
 	err error,
 	isValid bool,
+	duration time.Duration,
 ) {
 	atomic.AddUint64(&selectedNode.totalHits, 1)
 
 	selectedNode.statsMutex.Lock()
 	defer selectedNode.statsMutex.Unlock()
+	hasLatency := true
 	if err != nil {
-		netError, ok := err.(net.Error)
-		if errors.Is(err, context.DeadlineExceeded) || (ok && netError.Timeout()) {
+		if isTimeoutError(err) {
 			selectedNode.responseStats[http.StatusRequestTimeout] += 1
 		} else {
 			selectedNode.responseStats[0] += 1
+			// connection errors return immediately, they would make a broken node look fast
+			hasLatency = false
 		}
 	} else {
 		selectedNode.responseStats[res.StatusCode] += 1
 	}
 
+	failure := 0.0
 	if !isValid {
 		selectedNode.fails += 1
+		failure = 1
+	}
+
+	selectedNode.errorRate = ewma(selectedNode.errorRate, failure)
+	if hasLatency {
+		selectedNode.addLatencySample(duration)
+		if selectedNode.latency == 0 {
+			selectedNode.latency = duration
+		} else {
+			selectedNode.latency = time.Duration(ewma(float64(selectedNode.latency), float64(duration)))
+		}
 	}
 }
 
-func releaseResource(selectedChain *Chain, selectedNode *ChainNode) {
-	time.Sleep(selectedNode.limit.Per)
-	selectedChain.mutex.Lock()
-	selectedNode.hits -= 1
-	selectedChain.mutex.Unlock()
+// ewmaAlpha is the weight of the newest sample in moving averages of node metrics
+const ewmaAlpha = 0.2
+
+func ewma(average float64, sample float64) float64 {
+	return ewmaAlpha*sample + (1-ewmaAlpha)*average
+}
+
+func sleepContext(ctx context.Context, duration time.Duration) error {
+	if duration <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func isTimeoutError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	netError, ok := err.(net.Error)
+	return errors.Is(err, context.DeadlineExceeded) || (ok && netError.Timeout())
 }
 
 func isResponseValid(failureStatusCodes map[int]bool, res *Response, err error) bool {
@@ -0,0 +1,94 @@
+package eznode
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// attemptResult is the result of sending a request to one node
+type attemptResult struct {
+	node    *ChainNode
+	res     *Response
+	stream  *StreamResponse
+	err     error
+	isValid bool
+	trace   NodeTrace
+}
+
+// isNodeResult reports whether the result of an attempt says something about the node
+// a request which is cancelled by the caller, or by hedging, and a too large body are not faults of the node
+func isNodeResult(ctx context.Context, err error) bool {
+	return !(err != nil && (errors.Is(ctx.Err(), context.Canceled) || errors.Is(err, errBodyTooLarge)))
+}
+
+// attempt sends the request to the node which is already taken from the chain by getFreeNode
+func (e *EzNode) attempt(
+	ctx context.Context,
+	selectedChain *Chain,
+	selectedNode *ChainNode,
+	request *http.Request,
+	reqBody []byte,
+) attemptResult {
+	clonedReq := request.Clone(context.Background())
+	clonedReq.Body = io.NopCloser(bytes.NewBuffer(reqBody))
+	clonedReq = selectedNode.middleware(clonedReq)
+	ctxTimeout, cancelTimeout := context.WithTimeout(ctx, selectedNode.requestTimeout)
+	defer cancelTimeout()
+
+	startTime := time.Now()
+	res, err := e.apiCaller.DoRequest(withMaxBodySize(ctxTimeout, selectedChain.maxBodySize), clonedReq)
+	duration := time.Since(startTime)
+	atomic.AddInt64(&selectedNode.inFlight, -1)
+	if err == nil && selectedChain.maxBodySize > 0 && int64(len(res.Body)) > selectedChain.maxBodySize {
+		res, err = nil, errBodyTooLarge
+	}
+	isValid := isResponseValid(selectedChain.failureStatusCodes, res, err)
+	var validationErr error
+	if isValid && selectedChain.responseValidator != nil {
+		validationErr = selectedChain.responseValidator.Validate(res)
+		isValid = validationErr == nil
+	}
+	if isNodeResult(ctx, err) {
+		selectedChain.reportResult(selectedNode, res, err, isValid)
+		go collectMetric(selectedNode, res, err, isValid, duration)
+	} else {
+		selectedChain.reportAbandoned(selectedNode)
+	}
+
+	if isValid {
+		return attemptResult{
+			node:    selectedNode,
+			res:     res,
+			isValid: true,
+			trace: NodeTrace{
+				Time:       time.Now(),
+				NodeName:   selectedNode.name,
+				StatusCode: res.StatusCode,
+				Err:        nil,
+				Duration:   duration,
+			},
+		}
+	}
+
+	resStatusCode := 0
+	if res != nil {
+		resStatusCode = res.StatusCode
+	}
+
+	trace := generateTrace(selectedNode.name, err, resStatusCode, duration)
+	if validationErr != nil {
+		trace.Err = validationErr
+	}
+
+	return attemptResult{
+		node:  selectedNode,
+		res:   res,
+		err:   err,
+		trace: trace,
+	}
+}
@@ -0,0 +1,316 @@
+package eznode
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// BatchElem is a call of a JSON-RPC batch
+type BatchElem struct {
+	// Method of the call
+	Method string
+	// Params of the call, nil is sent as an empty list
+	Params any
+	// Result is decoded from the result of the call, it is not decoded if it is nil
+	Result any
+	// Error is set when the call fails, it is *JsonRpcError if the node responds with a JSON-RPC error
+	Error error
+}
+
+// BatchCall sends JSON-RPC calls of batch to the chain and sets Result or Error of every element
+// a free node is taken first, then a sub-batch of up to MaxBatchSize of that node, whose cost fits its limits, is sent to it
+// sub-batches are sent concurrently, a failed sub-batch is sent to another node up to RetryCount times
+// elements which still fail, or which are not answered, are retried individually by Call
+// elements which no node is free for get an EzNodeError, e.g. ErrorKindFullCapacity
+// returned error is only about the chain itself, errors of elements are set in their Error
+func (e *EzNode) BatchCall(
+	ctx context.Context,
+	chainId string,
+	batch []BatchElem,
+	options ...RequestOption,
+) error {
+	selectedChain := e.chains[chainId]
+	if selectedChain == nil {
+		return errors.New(fmt.Sprintf("cannot find chain id %s", chainId))
+	}
+
+	d := &batchDispatch{
+		ezNode:      e,
+		ctx:         ctx,
+		chain:       selectedChain,
+		batch:       batch,
+		params:      make([]json.RawMessage, len(batch)),
+		options:     options,
+		requestOpts: newRequestOptions(options),
+		wait:        &sync.WaitGroup{},
+	}
+
+	// elements which need different node tags cannot share a sub-batch
+	groups := make(map[string][]int)
+	groupKeys := make([]string, 0)
+	for i := range batch {
+		params := batch[i].Params
+		if params == nil {
+			params = []any{}
+		}
+
+		rawParams, err := json.Marshal(params)
+		if err != nil {
+			batch[i].Error = err
+			continue
+		}
+		d.params[i] = rawParams
+
+		request, body, _ := d.newRequest([]int{i})
+		tags := slices.Sorted(maps.Keys(selectedChain.requiredTags(request, body)))
+		key := strings.Join(tags, ",")
+		if _, ok := groups[key]; !ok {
+			groupKeys = append(groupKeys, key)
+		}
+		groups[key] = append(groups[key], i)
+	}
+
+	for _, key := range groupKeys {
+		d.wait.Add(1)
+		go func(indexes []int) {
+			defer d.wait.Done()
+			d.dispatch(indexes, make(map[string]bool), nil)
+		}(groups[key])
+	}
+	d.wait.Wait()
+
+	return nil
+}
+
+// takeBatch takes the extra cost of the largest sub-batch which fits the limits of the node
+// the node is already taken for a sub-batch of one, costOf returns the cost of a sub-batch of size and is called while the chain is locked
+func (c *Chain) takeBatch(node *ChainNode, class PriorityClass, maxSize int, costOf func(size int) uint) int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := time.Now()
+	reserve := c.reserveFor(class)
+	paidCost := nodeQuery{cost: costOf(1)}.requestCost()
+	extraCost := func(size int) float64 {
+		return nodeQuery{cost: costOf(size)}.requestCost() - paidCost
+	}
+
+	// cost of a sub-batch grows with its size, so the largest size which fits is searched
+	size := 1
+	for low, high := 2, maxSize; low <= high; {
+		middle := (low + high) / 2
+		if extra := extraCost(middle); extra <= 0 || node.allowRequest(now, extra, reserve) {
+			size = middle
+			low = middle + 1
+		} else {
+			high = middle - 1
+		}
+	}
+
+	if extra := extraCost(size); extra > 0 {
+		node.takeRequest(now, extra)
+	}
+	return size
+}
+
+// batchDispatch sends elements of a batch to nodes of the chain
+type batchDispatch struct {
+	ezNode      *EzNode
+	ctx         context.Context
+	chain       *Chain
+	batch       []BatchElem
+	params      []json.RawMessage
+	options     []RequestOption
+	requestOpts *requestOptions
+	wait        *sync.WaitGroup
+}
+
+// newRequest builds a batch request of the elements, it returns index of the element of each request id
+func (d *batchDispatch) newRequest(indexes []int) (*http.Request, []byte, map[string]int) {
+	indexOfId := make(map[string]int, len(indexes))
+	rpcRequests := make([]jsonRpcRequest, 0, len(indexes))
+	for _, i := range indexes {
+		rpcRequest := d.ezNode.newJsonRpcRequest(d.batch[i].Method, d.params[i])
+		indexOfId[strconv.FormatUint(rpcRequest.Id, 10)] = i
+		rpcRequests = append(rpcRequests, rpcRequest)
+	}
+
+	// requests only contain raw params, so they always marshal
+	body, _ := json.Marshal(rpcRequests)
+	request, _ := http.NewRequest(http.MethodPost, "", bytes.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+
+	return request, body, indexOfId
+}
+
+// dispatch takes free nodes and sends each of them a sub-batch of the elements which fits the node
+// trace has the attempts of the elements which failed as a whole on other nodes
+func (d *batchDispatch) dispatch(indexes []int, excludeNodes map[string]bool, trace []NodeTrace) {
+	for len(indexes) > 0 {
+		firstRequest, firstBody, _ := d.newRequest(indexes[:1])
+		selectedNode, err := d.chain.getFreeNode(d.ctx, nodeQuery{
+			excludeNodes: excludeNodes,
+			cost:         d.chain.requestCost(d.requestOpts, firstRequest, firstBody),
+			maxWait:      d.requestOpts.maxWait,
+			class:        d.requestOpts.class,
+			requiredTags: d.chain.requiredTags(firstRequest, firstBody),
+		})
+		if err != nil {
+			// sending the elements one by one would only wait for nodes again
+			for _, i := range indexes {
+				d.batch[i].Error = d.dispatchError(err, trace)
+			}
+			return
+		}
+
+		maxSize := len(indexes)
+		if selectedNode.maxBatchSize > 0 && selectedNode.maxBatchSize < maxSize {
+			maxSize = selectedNode.maxBatchSize
+		}
+
+		candidates := indexes
+		size := d.chain.takeBatch(selectedNode, d.requestOpts.class, maxSize, func(size int) uint {
+			request, body, _ := d.newRequest(candidates[:size])
+			return d.chain.requestCost(d.requestOpts, request, body)
+		})
+
+		chunk := indexes[:size]
+		indexes = indexes[size:]
+		d.wait.Add(1)
+		go func() {
+			defer d.wait.Done()
+			d.send(selectedNode, chunk, excludeNodes, trace)
+		}()
+	}
+}
+
+// dispatchError returns the error of elements which no node is taken for
+func (d *batchDispatch) dispatchError(err error, trace []NodeTrace) error {
+	metadata := ChainResponseMetadata{
+		ChainId: d.chain.id,
+		Retry:   len(trace),
+		Trace:   slices.Clone(trace),
+	}
+
+	if d.ctx.Err() != nil {
+		return newCanceledError(d.ctx.Err(), metadata)
+	}
+
+	if errors.Is(err, errNoMatchingNode) {
+		return newNoMatchingNodeError(metadata)
+	}
+
+	kind := ErrorKindFullCapacity
+	errorMessage := fmt.Sprintf("'%s' chain is at full capacity", d.chain.id)
+	if errors.Is(err, errQueueFull) {
+		kind = ErrorKindQueueFull
+		errorMessage = fmt.Sprintf("'%s' chain wait queue is full", d.chain.id)
+	} else if len(trace) > 0 {
+		kind = ErrorKindMaxRetries
+		errorMessage = fmt.Sprintf("'%s' chain batch failed and no other node is free to retry it", d.chain.id)
+	}
+
+	metadata.Trace = append(metadata.Trace, NodeTrace{
+		Time:       time.Now(),
+		StatusCode: http.StatusTooManyRequests,
+		Err:        errors.New(errorMessage),
+	})
+	return EzNodeError{
+		Message:  errorMessage,
+		Kind:     kind,
+		Metadata: metadata,
+	}
+}
+
+// send sends the sub-batch to the node which is already taken for it
+func (d *batchDispatch) send(selectedNode *ChainNode, chunk []int, excludeNodes map[string]bool, trace []NodeTrace) {
+	request, body, indexOfId := d.newRequest(chunk)
+	result := d.ezNode.attempt(d.ctx, d.chain, selectedNode, request, body)
+	answered, ok := d.readResult(chunk, indexOfId, result)
+	if !ok {
+		if len(trace) < d.chain.maxRetries(nil, d.chain.requiredTags(request, body)) && d.ctx.Err() == nil {
+			retryExcludeNodes := maps.Clone(excludeNodes)
+			retryExcludeNodes[selectedNode.name] = true
+			d.dispatch(chunk, retryExcludeNodes, append(slices.Clone(trace), result.trace))
+			return
+		}
+
+		d.callEach(chunk)
+		return
+	}
+
+	unanswered := make([]int, 0)
+	for _, i := range chunk {
+		if !answered[i] {
+			unanswered = append(unanswered, i)
+		}
+	}
+	d.callEach(unanswered)
+}
+
+// readResult sets results of the elements which the sub-batch response answers
+// it returns false if the node failed the whole sub-batch
+func (d *batchDispatch) readResult(chunk []int, indexOfId map[string]int, result attemptResult) (map[int]bool, bool) {
+	// a batch which fails validation because of some elements still answers the others
+	if result.err != nil || result.res == nil || d.chain.failureStatusCodes[result.res.StatusCode] {
+		return nil, false
+	}
+
+	var rawResponses []json.RawMessage
+	if err := json.Unmarshal(result.res.Body, &rawResponses); err != nil {
+		return nil, false
+	}
+
+	answered := make(map[int]bool, len(chunk))
+	for _, rawResponse := range rawResponses {
+		var rpcResponse jsonRpcResponse
+		if err := json.Unmarshal(rawResponse, &rpcResponse); err != nil {
+			continue
+		}
+
+		i, ok := indexOfId[string(rpcResponse.Id)]
+		if !ok || answered[i] {
+			continue
+		}
+
+		if rpcResponse.Error != nil {
+			if d.chain.responseValidator != nil && d.chain.responseValidator.Validate(&Response{
+				StatusCode: result.res.StatusCode,
+				Body:       rawResponse,
+				Headers:    result.res.Headers,
+			}) != nil {
+				continue
+			}
+
+			d.batch[i].Error = rpcResponse.Error
+		} else if d.batch[i].Result != nil {
+			if err := json.Unmarshal(rpcResponse.Result, d.batch[i].Result); err != nil {
+				d.batch[i].Error = errors.New(fmt.Sprintf("cannot decode result of %s: %v", d.batch[i].Method, err))
+			}
+		}
+		answered[i] = true
+	}
+
+	return answered, true
+}
+
+// callEach retries the elements individually by Call
+func (d *batchDispatch) callEach(indexes []int) {
+	for _, i := range indexes {
+		d.wait.Add(1)
+		go func(i int) {
+			defer d.wait.Done()
+			d.batch[i].Error = d.ezNode.Call(d.ctx, d.chain.id, d.batch[i].Method, d.params[i], d.batch[i].Result, d.options...)
+		}(i)
+	}
+}
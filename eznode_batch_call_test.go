@@ -0,0 +1,301 @@
+package eznode
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchCall(t *testing.T) {
+	t.Parallel()
+
+	type rpcRequest struct {
+		Id     uint64 `json:"id"`
+		Method string `json:"method"`
+	}
+
+	mutex := &sync.Mutex{}
+	batchSizes := make([]int, 0)
+	mockedApiCall := contextApiCall(func(ctx context.Context, request *http.Request) (*Response, error) {
+		body, _ := io.ReadAll(request.Body)
+		var calls []rpcRequest
+		if err := json.Unmarshal(body, &calls); err != nil {
+			var call rpcRequest
+			assert.Nil(t, json.Unmarshal(body, &call))
+			responseBody, _ := json.Marshal(map[string]any{"jsonrpc": "2.0", "id": call.Id, "result": call.Method + " retried"})
+			return &Response{StatusCode: http.StatusOK, Body: responseBody, Headers: &http.Header{}}, nil
+		}
+
+		mutex.Lock()
+		batchSizes = append(batchSizes, len(calls))
+		mutex.Unlock()
+
+		responses := make([]map[string]any, 0, len(calls))
+		for _, call := range calls {
+			switch call.Method {
+			case "flaky":
+				responses = append(responses, map[string]any{"jsonrpc": "2.0", "id": call.Id, "error": map[string]any{"code": -32000, "message": "header not found"}})
+			case "reverted":
+				responses = append(responses, map[string]any{"jsonrpc": "2.0", "id": call.Id, "error": map[string]any{"code": 3, "message": "execution reverted"}})
+			case "missing":
+			default:
+				responses = append(responses, map[string]any{"jsonrpc": "2.0", "id": call.Id, "result": call.Method})
+			}
+		}
+		slices.Reverse(responses)
+		responseBody, _ := json.Marshal(responses)
+
+		return &Response{StatusCode: http.StatusOK, Body: responseBody, Headers: &http.Header{}}, nil
+	})
+
+	chainNode1 := NewChainNode(NewChainNodeConfig{
+		Name: "Node 1",
+		Url:  "http://example.com",
+		Limit: ChainNodeLimit{
+			Count: 100,
+			Per:   1 * time.Minute,
+		},
+		RequestTimeout: 1 * time.Second,
+		Priority:       1,
+		MaxBatchSize:   3,
+	})
+
+	chainNode2 := NewChainNode(NewChainNodeConfig{
+		Name: "Node 2",
+		Url:  "http://example2.com",
+		Limit: ChainNodeLimit{
+			Count: 100,
+			Per:   1 * time.Minute,
+		},
+		RequestTimeout: 1 * time.Second,
+		Priority:       1,
+		MaxBatchSize:   2,
+	})
+
+	createdChain := NewChain(
+		NewChainConfig{
+			Id: "test-chain",
+			Nodes: []*ChainNode{
+				chainNode1,
+				chainNode2,
+			},
+			CheckTickRate: CheckTick{
+				TickRate:         100 * time.Millisecond,
+				MaxCheckDuration: 200 * time.Millisecond,
+			},
+			ResponseValidator: NewJsonRpcValidator(JsonRpcValidatorConfig{}),
+		},
+	)
+
+	ezNode := NewEzNode([]*Chain{createdChain}, WithApiClient(mockedApiCall))
+
+	results := make([]string, 5)
+	batch := []BatchElem{
+		{Method: "first", Result: &results[0]},
+		{Method: "flaky", Result: &results[1]},
+		{Method: "reverted", Result: &results[2]},
+		{Method: "missing", Result: &results[3]},
+		{Method: "last", Result: &results[4]},
+	}
+	err := ezNode.BatchCall(context.Background(), "test-chain", batch)
+	assert.Nil(t, err)
+
+	slices.Sort(batchSizes)
+	assert.Equal(t, []int{2, 3}, batchSizes, "each sub-batch should fit the node which serves it")
+
+	assert.Nil(t, batch[0].Error)
+	assert.Equal(t, "first", results[0])
+	assert.Nil(t, batch[1].Error)
+	assert.Equal(t, "flaky retried", results[1])
+	assert.EqualError(t, batch[2].Error, "json-rpc error 3: execution reverted")
+	assert.Nil(t, batch[3].Error)
+	assert.Equal(t, "missing retried", results[3])
+	assert.Nil(t, batch[4].Error)
+	assert.Equal(t, "last", results[4])
+}
+
+func TestBatchCallRetriesFailedSubBatch(t *testing.T) {
+	t.Parallel()
+
+	type rpcRequest struct {
+		Id     uint64 `json:"id"`
+		Method string `json:"method"`
+	}
+
+	mutex := &sync.Mutex{}
+	batchSizes := make(map[string][]int)
+	singleCalls := 0
+	mockedApiCall := contextApiCall(func(ctx context.Context, request *http.Request) (*Response, error) {
+		body, _ := io.ReadAll(request.Body)
+		var calls []rpcRequest
+		if err := json.Unmarshal(body, &calls); err != nil {
+			mutex.Lock()
+			singleCalls++
+			mutex.Unlock()
+			return &Response{StatusCode: http.StatusServiceUnavailable, Headers: &http.Header{}}, nil
+		}
+
+		mutex.Lock()
+		batchSizes[request.URL.Host] = append(batchSizes[request.URL.Host], len(calls))
+		mutex.Unlock()
+
+		if request.URL.Host == "example.com" {
+			return &Response{StatusCode: http.StatusServiceUnavailable, Headers: &http.Header{}}, nil
+		}
+
+		responses := make([]map[string]any, 0, len(calls))
+		for _, call := range calls {
+			responses = append(responses, map[string]any{"jsonrpc": "2.0", "id": call.Id, "result": call.Method})
+		}
+		responseBody, _ := json.Marshal(responses)
+
+		return &Response{StatusCode: http.StatusOK, Body: responseBody, Headers: &http.Header{}}, nil
+	})
+
+	chainNode1 := NewChainNode(NewChainNodeConfig{
+		Name: "Node 1",
+		Url:  "http://example.com",
+		Limit: ChainNodeLimit{
+			Count: 100,
+			Per:   1 * time.Minute,
+		},
+		RequestTimeout: 1 * time.Second,
+		Priority:       2,
+	})
+
+	chainNode2 := NewChainNode(NewChainNodeConfig{
+		Name: "Node 2",
+		Url:  "http://example2.com",
+		Limit: ChainNodeLimit{
+			Count: 100,
+			Per:   1 * time.Minute,
+		},
+		RequestTimeout: 1 * time.Second,
+		Priority:       1,
+	})
+
+	createdChain := NewChain(
+		NewChainConfig{
+			Id: "test-chain",
+			Nodes: []*ChainNode{
+				chainNode1,
+				chainNode2,
+			},
+			CheckTickRate: CheckTick{
+				TickRate:         100 * time.Millisecond,
+				MaxCheckDuration: 200 * time.Millisecond,
+			},
+			RetryCount: 1,
+		},
+	)
+
+	ezNode := NewEzNode([]*Chain{createdChain}, WithApiClient(mockedApiCall))
+
+	results := make([]string, 3)
+	batch := []BatchElem{
+		{Method: "first", Result: &results[0]},
+		{Method: "second", Result: &results[1]},
+		{Method: "third", Result: &results[2]},
+	}
+	err := ezNode.BatchCall(context.Background(), "test-chain", batch)
+	assert.Nil(t, err)
+
+	assert.Equal(t, []int{3}, batchSizes["example.com"])
+	assert.Equal(t, []int{3}, batchSizes["example2.com"], "failed sub-batch should be retried as a batch on another node")
+	assert.Equal(t, 0, singleCalls, "failed sub-batch should not be split into single calls")
+	for i, method := range []string{"first", "second", "third"} {
+		assert.Nil(t, batch[i].Error)
+		assert.Equal(t, method, results[i])
+	}
+}
+
+func TestBatchCallFitsNodeLimits(t *testing.T) {
+	t.Parallel()
+
+	type rpcRequest struct {
+		Id     uint64 `json:"id"`
+		Method string `json:"method"`
+	}
+
+	mutex := &sync.Mutex{}
+	batchSizes := make([]int, 0)
+	singleCalls := 0
+	mockedApiCall := contextApiCall(func(ctx context.Context, request *http.Request) (*Response, error) {
+		body, _ := io.ReadAll(request.Body)
+		var calls []rpcRequest
+		if err := json.Unmarshal(body, &calls); err != nil {
+			mutex.Lock()
+			singleCalls++
+			mutex.Unlock()
+			return &Response{StatusCode: http.StatusOK, Body: []byte(`{"jsonrpc":"2.0","id":1,"result":"0x1"}`), Headers: &http.Header{}}, nil
+		}
+
+		mutex.Lock()
+		batchSizes = append(batchSizes, len(calls))
+		mutex.Unlock()
+
+		responses := make([]map[string]any, 0, len(calls))
+		for _, call := range calls {
+			responses = append(responses, map[string]any{"jsonrpc": "2.0", "id": call.Id, "result": call.Method})
+		}
+		responseBody, _ := json.Marshal(responses)
+
+		return &Response{StatusCode: http.StatusOK, Body: responseBody, Headers: &http.Header{}}, nil
+	})
+
+	chainNode1 := NewChainNode(NewChainNodeConfig{
+		Name: "Node 1",
+		Url:  "http://example.com",
+		Limit: ChainNodeLimit{
+			Count: 10,
+			Per:   1 * time.Minute,
+		},
+		RequestTimeout: 1 * time.Second,
+		Priority:       1,
+	})
+
+	createdChain := NewChain(
+		NewChainConfig{
+			Id: "test-chain",
+			Nodes: []*ChainNode{
+				chainNode1,
+			},
+			CheckTickRate: CheckTick{
+				TickRate:         100 * time.Millisecond,
+				MaxCheckDuration: 200 * time.Millisecond,
+			},
+			CostFunc: JsonRpcMethodCost(nil, 1),
+		},
+	)
+
+	ezNode := NewEzNode([]*Chain{createdChain}, WithApiClient(mockedApiCall))
+
+	batch := make([]BatchElem, 30)
+	for i := range batch {
+		batch[i] = BatchElem{Method: "eth_chainId"}
+	}
+	err := ezNode.BatchCall(context.Background(), "test-chain", batch)
+	assert.Nil(t, err)
+
+	assert.Equal(t, []int{10}, batchSizes, "sub-batch should not cost more than the node limit allows")
+	assert.Equal(t, 0, singleCalls, "elements should not be sent one by one when no node is free")
+	assert.Equal(t, uint(10), ezNode.GetStats()[0].Nodes[0].CurrentHits)
+
+	failed := 0
+	for i := range batch {
+		var ezNodeError EzNodeError
+		if errors.As(batch[i].Error, &ezNodeError) {
+			assert.Equal(t, ErrorKindFullCapacity, ezNodeError.Kind)
+			failed += 1
+		}
+	}
+	assert.Equal(t, 20, failed)
+}
@@ -0,0 +1,8 @@
+package eznode
+
+// WithCache sets the cache of responses, requests are cached by CachePolicy of their chain
+func WithCache(cache Cache) Option {
+	return func(ezNode *EzNode) {
+		ezNode.cache = cache
+	}
+}
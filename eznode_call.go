@@ -0,0 +1,93 @@
+package eznode
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+)
+
+// jsonRpcRequest is the envelope of a JSON-RPC 2.0 request
+type jsonRpcRequest struct {
+	JsonRpc string `json:"jsonrpc"`
+	Id      uint64 `json:"id"`
+	Method  string `json:"method"`
+	Params  any    `json:"params"`
+}
+
+// jsonRpcResponse is the envelope of a JSON-RPC 2.0 response
+type jsonRpcResponse struct {
+	Id     json.RawMessage `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *JsonRpcError   `json:"error"`
+}
+
+func (e *EzNode) newJsonRpcRequest(method string, params any) jsonRpcRequest {
+	if params == nil {
+		params = []any{}
+	}
+
+	return jsonRpcRequest{
+		JsonRpc: "2.0",
+		Id:      atomic.AddUint64(&e.jsonRpcId, 1),
+		Method:  method,
+		Params:  params,
+	}
+}
+
+// Call sends a JSON-RPC 2.0 request to the chain and decodes the result into result
+// params is marshalled as is, nil is sent as an empty list, result is not decoded if it is nil
+// If the node responds with a JSON-RPC error, it is returned as *JsonRpcError
+// errors of sending the request are returned the same as SendRequest
+func (e *EzNode) Call(
+	ctx context.Context,
+	chainId string,
+	method string,
+	params any,
+	result any,
+	options ...RequestOption,
+) error {
+	rpcRequest := e.newJsonRpcRequest(method, params)
+	body, err := json.Marshal(rpcRequest)
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequest(http.MethodPost, "", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	res, err := e.SendRequest(ctx, chainId, request, options...)
+	if err != nil {
+		return err
+	}
+
+	var rpcResponse jsonRpcResponse
+	if err := json.Unmarshal(res.Body, &rpcResponse); err != nil {
+		return errors.New(fmt.Sprintf("cannot decode json-rpc response of %s: %v", method, err))
+	}
+
+	if string(rpcResponse.Id) != strconv.FormatUint(rpcRequest.Id, 10) {
+		return errors.New(fmt.Sprintf("json-rpc response id %s does not match request id %d", rpcResponse.Id, rpcRequest.Id))
+	}
+
+	if rpcResponse.Error != nil {
+		return rpcResponse.Error
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	if err := json.Unmarshal(rpcResponse.Result, result); err != nil {
+		return errors.New(fmt.Sprintf("cannot decode result of %s: %v", method, err))
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package eznode
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCall(t *testing.T) {
+	t.Parallel()
+
+	mockedApiCall := contextApiCall(func(ctx context.Context, request *http.Request) (*Response, error) {
+		assert.Equal(t, http.MethodPost, request.Method)
+		assert.Equal(t, "http://example.com", request.URL.String())
+		assert.Equal(t, "application/json", request.Header.Get("Content-Type"))
+
+		var rpcRequest struct {
+			JsonRpc string          `json:"jsonrpc"`
+			Id      uint64          `json:"id"`
+			Method  string          `json:"method"`
+			Params  json.RawMessage `json:"params"`
+		}
+		body, _ := io.ReadAll(request.Body)
+		assert.Nil(t, json.Unmarshal(body, &rpcRequest))
+		assert.Equal(t, "2.0", rpcRequest.JsonRpc)
+
+		response := map[string]any{"jsonrpc": "2.0", "id": rpcRequest.Id}
+		switch rpcRequest.Method {
+		case "eth_blockNumber":
+			assert.Equal(t, "[]", string(rpcRequest.Params))
+			response["result"] = "0x10"
+		case "eth_call":
+			response["error"] = map[string]any{"code": 3, "message": "execution reverted", "data": "0x01"}
+		}
+		responseBody, _ := json.Marshal(response)
+
+		return &Response{
+			StatusCode: http.StatusOK,
+			Body:       responseBody,
+			Headers:    &http.Header{},
+		}, nil
+	})
+
+	chainNode1 := NewChainNode(NewChainNodeConfig{
+		Name: "Node 1",
+		Url:  "http://example.com",
+		Limit: ChainNodeLimit{
+			Count: 10,
+			Per:   1 * time.Minute,
+		},
+		RequestTimeout: 1 * time.Second,
+		Priority:       1,
+	})
+
+	createdChain := NewChain(
+		NewChainConfig{
+			Id: "test-chain",
+			Nodes: []*ChainNode{
+				chainNode1,
+			},
+			CheckTickRate: CheckTick{
+				TickRate:         100 * time.Millisecond,
+				MaxCheckDuration: 200 * time.Millisecond,
+			},
+		},
+	)
+
+	ezNode := NewEzNode([]*Chain{createdChain}, WithApiClient(mockedApiCall))
+
+	var blockNumber string
+	err := ezNode.Call(context.Background(), "test-chain", "eth_blockNumber", nil, &blockNumber)
+	assert.Nil(t, err)
+	assert.Equal(t, "0x10", blockNumber)
+
+	err = ezNode.Call(context.Background(), "test-chain", "eth_call", []any{map[string]string{"to": "0x0"}, "latest"}, nil)
+	var rpcError *JsonRpcError
+	assert.True(t, errors.As(err, &rpcError))
+	assert.Equal(t, 3, rpcError.Code)
+	assert.Equal(t, "execution reverted", rpcError.Message)
+	assert.Equal(t, `"0x01"`, string(rpcError.Data))
+
+	err = ezNode.Call(context.Background(), "unknown-chain", "eth_blockNumber", nil, &blockNumber)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,150 @@
+package eznode
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+)
+
+// WithCoalescing shares one upstream request between identical requests of the chain which are in flight at the same time
+// requests are identical when their method, url, body, included nodes and options are the same, ids of JSON-RPC requests are ignored
+// callers which receive the response of another request have Metadata.Coalesced set
+// the upstream request is not cancelled with the caller which starts it, it is cancelled once every caller is cancelled
+func WithCoalescing() RequestOption {
+	return func(requestOpts *requestOptions) {
+		requestOpts.coalesce = true
+	}
+}
+
+// coalescing tracks in flight requests which other identical requests can join
+type coalescing struct {
+	calls map[string]*coalescedCall
+	mutex *sync.Mutex
+}
+
+// coalescedCall is an upstream request, waiters and cancel are protected by the mutex of coalescing
+type coalescedCall struct {
+	done      chan struct{}
+	leaderRes *Response
+	res       *Response
+	err       error
+	waiters   int
+	cancel    context.CancelFunc
+}
+
+func coalescingKey(chainId string, request *http.Request, body []byte, includeNodeList []string, requestOpts *requestOptions) string {
+	// a request joins only a call which is sent with the same options, e.g. a critical request does not wait behind a background one
+	key := requestKey(chainId, request, body) + fmt.Sprintf("|%d|%d|%d", requestOpts.cost, requestOpts.maxWait, requestOpts.class)
+	if requestOpts.hedge != nil {
+		key += fmt.Sprintf("|%d|%g", requestOpts.hedge.Delay, requestOpts.hedge.Percentile)
+	}
+
+	return withIncludeNodes(key, includeNodeList)
+}
+
+// do calls send unless an identical request is in flight, then it waits for and returns a copy of its result
+// send runs with a context which keeps values of ctx but is cancelled only when every caller of the request is cancelled
+// metadata is used for the error of a caller which is cancelled while waiting
+func (c *coalescing) do(
+	ctx context.Context,
+	key string,
+	reqBody []byte,
+	metadata ChainResponseMetadata,
+	send func(ctx context.Context) (*Response, error),
+) (*Response, error) {
+	c.mutex.Lock()
+	call, coalesced := c.calls[key]
+	if !coalesced {
+		sendCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		call = &coalescedCall{
+			done:   make(chan struct{}),
+			cancel: cancel,
+		}
+		c.calls[key] = call
+		go c.send(sendCtx, key, call, send)
+	}
+	call.waiters += 1
+	c.mutex.Unlock()
+
+	select {
+	case <-ctx.Done():
+		c.leave(key, call)
+		metadata.Coalesced = coalesced
+		return nil, newCanceledError(ctx.Err(), metadata)
+	case <-call.done:
+	}
+
+	if !coalesced {
+		return call.leaderRes, call.err
+	}
+
+	return call.result(reqBody)
+}
+
+// send runs the upstream request of the call and shares its result with the callers
+func (c *coalescing) send(
+	ctx context.Context,
+	key string,
+	call *coalescedCall,
+	send func(ctx context.Context) (*Response, error),
+) {
+	defer call.cancel()
+
+	res, err := send(ctx)
+	if res != nil {
+		// the caller which started the call owns res, coalesced callers get copies of a snapshot
+		call.leaderRes = res
+		call.res = copyResponse(res)
+		call.res.Metadata = res.Metadata
+		call.res.Metadata.Trace = slices.Clone(res.Metadata.Trace)
+	}
+	call.err = err
+
+	c.mutex.Lock()
+	if c.calls[key] == call {
+		delete(c.calls, key)
+	}
+	c.mutex.Unlock()
+	close(call.done)
+}
+
+// leave removes a cancelled caller from the call, the upstream request is cancelled when no caller is left
+func (c *coalescing) leave(key string, call *coalescedCall) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	call.waiters -= 1
+	if call.waiters > 0 {
+		return
+	}
+
+	// later identical requests start a new call instead of joining the cancelled one
+	if c.calls[key] == call {
+		delete(c.calls, key)
+	}
+	call.cancel()
+}
+
+// result returns a copy of the result of the call for a coalesced caller
+func (c *coalescedCall) result(reqBody []byte) (*Response, error) {
+	if c.err != nil {
+		if ezNodeError, ok := c.err.(EzNodeError); ok {
+			ezNodeError.Metadata.Coalesced = true
+			return nil, ezNodeError
+		}
+
+		return nil, c.err
+	}
+
+	res := copyResponse(c.res)
+	if call, ok := parseJsonRpcCall(reqBody); ok {
+		res.Body = withJsonRpcId(res.Body, call.Id)
+	}
+	res.Metadata = c.res.Metadata
+	res.Metadata.Trace = slices.Clone(c.res.Metadata.Trace)
+	res.Metadata.Coalesced = true
+
+	return res, nil
+}
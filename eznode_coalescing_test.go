@@ -0,0 +1,201 @@
+package eznode
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCoalescing(t *testing.T) {
+	t.Parallel()
+
+	var upstreamCalls int64
+	mockedApiCall := contextApiCall(func(ctx context.Context, request *http.Request) (*Response, error) {
+		atomic.AddInt64(&upstreamCalls, 1)
+		body, _ := io.ReadAll(request.Body)
+		call, _ := parseJsonRpcCall(body)
+		time.Sleep(100 * time.Millisecond)
+
+		return &Response{
+			StatusCode: http.StatusOK,
+			Body:       []byte(`{"jsonrpc":"2.0","id":` + string(call.Id) + `,"result":"0x1"}`),
+			Headers:    &http.Header{},
+		}, nil
+	})
+
+	chainNode1 := NewChainNode(NewChainNodeConfig{
+		Name: "Node 1",
+		Url:  "http://example.com",
+		Limit: ChainNodeLimit{
+			Count: 100,
+			Per:   1 * time.Minute,
+		},
+		RequestTimeout: 1 * time.Second,
+		Priority:       1,
+	})
+
+	createdChain := NewChain(
+		NewChainConfig{
+			Id: "test-chain",
+			Nodes: []*ChainNode{
+				chainNode1,
+			},
+			CheckTickRate: CheckTick{
+				TickRate:         100 * time.Millisecond,
+				MaxCheckDuration: 200 * time.Millisecond,
+			},
+		},
+	)
+
+	ezNode := NewEzNode([]*Chain{createdChain}, WithApiClient(mockedApiCall))
+
+	var coalescedCount int64
+	w := &sync.WaitGroup{}
+	for i := 1; i <= 10; i++ {
+		w.Add(1)
+		go func(id int) {
+			defer w.Done()
+			body := `{"jsonrpc":"2.0","id":` + strconv.Itoa(id) + `,"method":"eth_getBlockByNumber","params":["0x1",false]}`
+			request, _ := http.NewRequest("POST", "", bytes.NewBufferString(body))
+			res, err := ezNode.SendRequest(context.Background(), "test-chain", request, WithCoalescing())
+			assert.Nil(t, err)
+			assert.JSONEq(t, `{"jsonrpc":"2.0","id":`+strconv.Itoa(id)+`,"result":"0x1"}`, string(res.Body))
+			assert.Equal(t, 1, len(res.Metadata.Trace))
+			if res.Metadata.Coalesced {
+				atomic.AddInt64(&coalescedCount, 1)
+			}
+		}(i)
+	}
+	w.Wait()
+
+	assert.Equal(t, int64(1), atomic.LoadInt64(&upstreamCalls))
+	assert.Equal(t, int64(9), atomic.LoadInt64(&coalescedCount))
+
+	request, _ := http.NewRequest("POST", "", bytes.NewBufferString(`{"jsonrpc":"2.0","id":1,"method":"eth_getBlockByNumber","params":["0x1",false]}`))
+	res, err := ezNode.SendRequest(context.Background(), "test-chain", request, WithCoalescing())
+	assert.Nil(t, err)
+	assert.False(t, res.Metadata.Coalesced)
+	assert.Equal(t, int64(2), atomic.LoadInt64(&upstreamCalls), "finished requests should not be shared")
+}
+
+func TestCoalescingLeaderCancelled(t *testing.T) {
+	t.Parallel()
+
+	var upstreamCalls int64
+	upstreamCancelled := make(chan struct{}, 1)
+	mockedApiCall := contextApiCall(func(ctx context.Context, request *http.Request) (*Response, error) {
+		atomic.AddInt64(&upstreamCalls, 1)
+		body, _ := io.ReadAll(request.Body)
+		call, _ := parseJsonRpcCall(body)
+		select {
+		case <-ctx.Done():
+			upstreamCancelled <- struct{}{}
+			return nil, ctx.Err()
+		case <-time.After(200 * time.Millisecond):
+		}
+
+		return &Response{
+			StatusCode: http.StatusOK,
+			Body:       []byte(`{"jsonrpc":"2.0","id":` + string(call.Id) + `,"result":"0x1"}`),
+			Headers:    &http.Header{},
+		}, nil
+	})
+
+	chainNode1 := NewChainNode(NewChainNodeConfig{
+		Name: "Node 1",
+		Url:  "http://example.com",
+		Limit: ChainNodeLimit{
+			Count: 100,
+			Per:   1 * time.Minute,
+		},
+		RequestTimeout: 1 * time.Second,
+		Priority:       1,
+	})
+
+	createdChain := NewChain(
+		NewChainConfig{
+			Id: "test-chain",
+			Nodes: []*ChainNode{
+				chainNode1,
+			},
+			CheckTickRate: CheckTick{
+				TickRate:         100 * time.Millisecond,
+				MaxCheckDuration: 200 * time.Millisecond,
+			},
+		},
+	)
+
+	ezNode := NewEzNode([]*Chain{createdChain}, WithApiClient(mockedApiCall))
+	newRequest := func(id int) *http.Request {
+		body := `{"jsonrpc":"2.0","id":` + strconv.Itoa(id) + `,"method":"eth_getBlockByNumber","params":["0x1",false]}`
+		request, _ := http.NewRequest("POST", "", bytes.NewBufferString(body))
+		return request
+	}
+
+	leaderCtx, cancelLeader := context.WithCancel(context.Background())
+	defer cancelLeader()
+	w := &sync.WaitGroup{}
+	w.Add(1)
+	go func() {
+		defer w.Done()
+		_, err := ezNode.SendRequest(leaderCtx, "test-chain", newRequest(1), WithCoalescing())
+		var ezNodeError EzNodeError
+		assert.ErrorAs(t, err, &ezNodeError)
+		assert.Equal(t, ErrorKindCanceled, ezNodeError.Kind)
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	for i := 2; i <= 4; i++ {
+		w.Add(1)
+		go func(id int) {
+			defer w.Done()
+			res, err := ezNode.SendRequest(context.Background(), "test-chain", newRequest(id), WithCoalescing())
+			assert.Nil(t, err, "followers should not fail with the leader")
+			assert.JSONEq(t, `{"jsonrpc":"2.0","id":`+strconv.Itoa(id)+`,"result":"0x1"}`, string(res.Body))
+			assert.True(t, res.Metadata.Coalesced)
+		}(i)
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	cancelLeader()
+	w.Wait()
+	assert.Equal(t, int64(1), atomic.LoadInt64(&upstreamCalls))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+	_, err := ezNode.SendRequest(ctx, "test-chain", newRequest(5), WithCoalescing())
+	assert.ErrorIs(t, err, context.Canceled)
+
+	select {
+	case <-upstreamCancelled:
+	case <-time.After(100 * time.Millisecond):
+		assert.Fail(t, "upstream request should be cancelled when every caller is cancelled")
+	}
+}
+
+func TestCoalescingKeyOptions(t *testing.T) {
+	t.Parallel()
+
+	request, _ := http.NewRequest("POST", "", nil)
+	body := []byte(`{"jsonrpc":"2.0","id":1,"method":"eth_blockNumber"}`)
+	keyOf := func(options ...RequestOption) string {
+		return coalescingKey("test-chain", request, body, nil, newRequestOptions(options))
+	}
+
+	assert.Equal(t, keyOf(WithCoalescing()), keyOf())
+	assert.NotEqual(t, keyOf(WithPriorityClass(PriorityBackground)), keyOf(WithPriorityClass(PriorityCritical)), "should not join a call of another class")
+	assert.NotEqual(t, keyOf(WithMaxWait(time.Second)), keyOf())
+	assert.NotEqual(t, keyOf(WithCost(5)), keyOf())
+	assert.NotEqual(t, keyOf(WithHedge(HedgeConfig{Delay: time.Second})), keyOf())
+}
@@ -23,6 +23,7 @@ func (e *EzNode) DisableNodeWithTime(chainId string, nodeName string, duration t
 }
 
 // EnableNode enables a node from a chain
+// it also closes the node circuit breaker if it is open
 func (e *EzNode) EnableNode(chainId string, nodeName string) {
 	for _, chain := range e.chains {
 		if chain.id == chainId {
@@ -0,0 +1,114 @@
+package eznode
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// HedgeConfig determines when a second request is sent to another node while the first one is not answered yet
+type HedgeConfig struct {
+	// Delay is how long to wait for the first node before hedging
+	// the request is not hedged if neither Delay nor Percentile is greater than 0
+	Delay time.Duration
+	// Percentile derives the delay from the response time of the first node, e.g. 0.95 for p95
+	// Delay is used until the node has enough samples, or half of RequestTimeout of the node if Delay is not set
+	// Percentile is optional
+	Percentile float64
+}
+
+// minHedgeSamples is the number of latency samples a node needs before its percentile is trusted
+const minHedgeSamples = 10
+
+func (h *HedgeConfig) delayFor(node *ChainNode) time.Duration {
+	if h.Percentile > 0 {
+		if delay, ok := node.latencyPercentile(h.Percentile, minHedgeSamples); ok {
+			return delay
+		}
+	}
+
+	if h.Delay > 0 {
+		return h.Delay
+	}
+
+	return node.requestTimeout / 2
+}
+
+// WithHedge sends the request to a second free node if the first node does not answer within the hedge delay
+// the first valid response is returned and the other request is cancelled
+// the request is not hedged if neither Delay nor Percentile is greater than 0, or while other requests wait for a free node
+func WithHedge(hedge HedgeConfig) RequestOption {
+	return func(requestOpts *requestOptions) {
+		if hedge.Delay <= 0 && hedge.Percentile <= 0 {
+			requestOpts.hedge = nil
+			return
+		}
+
+		requestOpts.hedge = &hedge
+	}
+}
+
+// hedgedAttempt sends the request to the node and to a hedge node if the node is slow
+// results are in the order which requests are sent
+func (e *EzNode) hedgedAttempt(
+	ctx context.Context,
+	selectedChain *Chain,
+	selectedNode *ChainNode,
+	query nodeQuery,
+	request *http.Request,
+	reqBody []byte,
+	hedge *HedgeConfig,
+) []attemptResult {
+	hedgeCtx, cancelHedge := context.WithCancel(ctx)
+	defer cancelHedge()
+
+	type indexedResult struct {
+		index  int
+		result attemptResult
+	}
+	resultChan := make(chan indexedResult, 2)
+	send := func(index int, node *ChainNode) {
+		resultChan <- indexedResult{
+			index:  index,
+			result: e.attempt(hedgeCtx, selectedChain, node, request, reqBody),
+		}
+	}
+
+	results := make([]attemptResult, 1, 2)
+	go send(0, selectedNode)
+	pending := 1
+
+	hedgeTimer := time.NewTimer(hedge.delayFor(selectedNode))
+	defer hedgeTimer.Stop()
+	hedgeTimerC := hedgeTimer.C
+
+	for pending > 0 {
+		select {
+		case <-hedgeTimerC:
+			hedgeTimerC = nil
+
+			hedgeQuery := query
+			hedgeQuery.excludeNodes = make(map[string]bool, len(query.excludeNodes)+1)
+			for name := range query.excludeNodes {
+				hedgeQuery.excludeNodes[name] = true
+			}
+			hedgeQuery.excludeNodes[selectedNode.name] = true
+
+			hedgeNode := selectedChain.findNode(hedgeQuery)
+			if hedgeNode != nil {
+				results = append(results, attemptResult{})
+				go send(1, hedgeNode)
+				pending += 1
+			}
+		case indexed := <-resultChan:
+			results[indexed.index] = indexed.result
+			pending -= 1
+
+			if indexed.result.isValid {
+				cancelHedge()
+			}
+		}
+	}
+
+	return results
+}
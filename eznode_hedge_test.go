@@ -0,0 +1,188 @@
+package eznode
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type contextApiCall func(ctx context.Context, request *http.Request) (*Response, error)
+
+func (c contextApiCall) DoRequest(ctx context.Context, request *http.Request) (*Response, error) {
+	return c(ctx, request)
+}
+
+func TestHedgedRequest(t *testing.T) {
+	t.Parallel()
+
+	mockedApiCall := contextApiCall(func(ctx context.Context, request *http.Request) (*Response, error) {
+		if request.URL.Host == "example.com" {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(500 * time.Millisecond):
+			}
+		}
+
+		return &Response{
+			StatusCode: http.StatusOK,
+			Body:       []byte(request.URL.Host),
+			Headers:    &http.Header{},
+		}, nil
+	})
+
+	chainNode1 := NewChainNode(NewChainNodeConfig{
+		Name: "Node 1",
+		Url:  "http://example.com",
+		Limit: ChainNodeLimit{
+			Count: 10,
+			Per:   1 * time.Minute,
+		},
+		RequestTimeout: 1 * time.Second,
+		Priority:       2,
+	})
+
+	chainNode2 := NewChainNode(NewChainNodeConfig{
+		Name: "Node 2",
+		Url:  "http://example2.com",
+		Limit: ChainNodeLimit{
+			Count: 10,
+			Per:   1 * time.Minute,
+		},
+		RequestTimeout: 1 * time.Second,
+		Priority:       1,
+	})
+
+	createdChain := NewChain(
+		NewChainConfig{
+			Id: "test-chain",
+			Nodes: []*ChainNode{
+				chainNode1,
+				chainNode2,
+			},
+			CheckTickRate: CheckTick{
+				TickRate:         100 * time.Millisecond,
+				MaxCheckDuration: 200 * time.Millisecond,
+			},
+			RetryCount: 2,
+		},
+	)
+
+	ezNode := NewEzNode([]*Chain{createdChain}, WithApiClient(mockedApiCall))
+	request, _ := http.NewRequest("GET", "/", nil)
+
+	startTime := time.Now()
+	res, err := ezNode.SendRequest(
+		context.Background(),
+		"test-chain",
+		request,
+		WithHedge(HedgeConfig{Delay: 50 * time.Millisecond}),
+	)
+	assert.Nil(t, err)
+	assert.Less(t, time.Since(startTime), 400*time.Millisecond)
+	assert.Equal(t, "example2.com", string(res.Body))
+	assert.Equal(t, 2, len(res.Metadata.Trace))
+	assert.Equal(t, chainNode1.name, res.Metadata.Trace[0].NodeName)
+	assert.ErrorIs(t, res.Metadata.Trace[0].Err, context.Canceled)
+	assert.Equal(t, chainNode2.name, res.Metadata.Trace[1].NodeName)
+	assert.Equal(t, uint(1), chainNode1.hits())
+	assert.Equal(t, uint(1), chainNode2.hits())
+	assert.Equal(t, 0.0, chainNode1.ErrorRate(), "cancelled hedge should not count as failure")
+}
+
+func TestHedgeDelayFromPercentile(t *testing.T) {
+	t.Parallel()
+
+	chainNode1 := NewChainNode(NewChainNodeConfig{
+		Name: "Node 1",
+		Url:  "http://example.com",
+		Limit: ChainNodeLimit{
+			Count: 10,
+			Per:   1 * time.Second,
+		},
+		RequestTimeout: 1 * time.Second,
+		Priority:       1,
+	})
+
+	hedge := &HedgeConfig{
+		Delay:      time.Second,
+		Percentile: 0.95,
+	}
+	assert.Equal(t, time.Second, hedge.delayFor(chainNode1), "should use fixed delay without samples")
+
+	okResponse := &Response{StatusCode: http.StatusOK}
+	for i := 1; i <= 100; i++ {
+		collectMetric(chainNode1, okResponse, nil, true, time.Duration(i)*time.Millisecond)
+	}
+	assert.Equal(t, 95*time.Millisecond, hedge.delayFor(chainNode1))
+}
+
+func TestHedgeWithoutDelay(t *testing.T) {
+	t.Parallel()
+
+	requestOpts := newRequestOptions([]RequestOption{WithHedge(HedgeConfig{})})
+	assert.Nil(t, requestOpts.hedge, "zero delay should not hedge every request")
+
+	requestOpts = newRequestOptions([]RequestOption{WithHedge(HedgeConfig{Delay: time.Millisecond})})
+	assert.NotNil(t, requestOpts.hedge)
+
+	requestOpts = newRequestOptions([]RequestOption{WithHedge(HedgeConfig{Percentile: 0.95})})
+	assert.NotNil(t, requestOpts.hedge, "percentile should enable hedging without delay")
+
+	chainNode1 := NewChainNode(NewChainNodeConfig{
+		Name: "Node 1",
+		Url:  "http://example.com",
+		Limit: ChainNodeLimit{
+			Count: 10,
+			Per:   1 * time.Second,
+		},
+		RequestTimeout: 1 * time.Second,
+		Priority:       1,
+	})
+	assert.Equal(t, 500*time.Millisecond, requestOpts.hedge.delayFor(chainNode1), "should fall back to half of request timeout without samples")
+}
+
+func TestHedgeYieldsToWaitQueue(t *testing.T) {
+	t.Parallel()
+
+	chainNode1 := NewChainNode(NewChainNodeConfig{
+		Name: "Node 1",
+		Url:  "http://example.com",
+		Limit: ChainNodeLimit{
+			Count: 10,
+			Per:   1 * time.Minute,
+		},
+		RequestTimeout: 1 * time.Second,
+		Priority:       1,
+	})
+
+	createdChain := NewChain(
+		NewChainConfig{
+			Id: "test-chain",
+			Nodes: []*ChainNode{
+				chainNode1,
+			},
+			CheckTickRate: CheckTick{
+				TickRate:         100 * time.Millisecond,
+				MaxCheckDuration: 200 * time.Millisecond,
+			},
+		},
+	)
+
+	queued := &waiter{
+		query: nodeQuery{includeNodes: map[string]bool{"Unknown Node": true}},
+		node:  make(chan *ChainNode, 1),
+	}
+	createdChain.mutex.Lock()
+	createdChain.waitQueue.add(queued)
+	createdChain.mutex.Unlock()
+	assert.Nil(t, createdChain.findNode(nodeQuery{}), "hedge should not take capacity ahead of waiters")
+
+	createdChain.mutex.Lock()
+	createdChain.waitQueue.remove(queued)
+	createdChain.mutex.Unlock()
+	assert.NotNil(t, createdChain.findNode(nodeQuery{}))
+}
@@ -20,6 +20,8 @@ func (e *EzNode) LoadStats(loadedStats []ChainStats) {
 					node.statsMutex.Lock()
 					node.responseStats = loadedNode.ResponseStats
 					node.fails = loadedNode.Fails
+					node.latency = loadedNode.Latency
+					node.errorRate = loadedNode.ErrorRate
 					node.statsMutex.Unlock()
 				}
 			}
@@ -0,0 +1,271 @@
+package eznode
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// QuorumConfig determines how many nodes a quorum request is sent to and how many of them must agree
+type QuorumConfig struct {
+	// Nodes is the max number of distinct nodes which the request is sent to
+	Nodes int
+	// Agree is the number of nodes which must respond the same for the response to be returned
+	Agree int
+	// Normalize returns the part of a response which is compared between nodes, e.g. result of a JSON-RPC response
+	// a node which Normalize returns an error for is counted as failed
+	// Normalize is optional, response bodies are compared as is by default
+	Normalize func(response *Response) ([]byte, error)
+}
+
+// QuorumMetadata is the answer of every node of a quorum request
+type QuorumMetadata struct {
+	// Agreed is the number of nodes which agreed on the returned response
+	Agreed int
+	// Answers are answers of nodes in the order which requests are sent
+	Answers []QuorumAnswer
+	// Dissenters are names of nodes which failed or responded differently from the majority
+	Dissenters []string
+}
+
+// QuorumAnswer is the answer of a node to a quorum request
+type QuorumAnswer struct {
+	// NodeName is the node that the request was sent to
+	NodeName string
+	// Answer is the normalized response of the node, it is nil if the node failed
+	Answer []byte
+	// Err is the error of the node or of normalizing its response
+	Err error
+}
+
+// NormalizeJsonRpcResult returns result, or error, of a JSON-RPC response without formatting and id
+// it can be used as Normalize of QuorumConfig
+func NormalizeJsonRpcResult(response *Response) ([]byte, error) {
+	var rpcResponse jsonRpcResponse
+	if err := json.Unmarshal(response.Body, &rpcResponse); err != nil {
+		return nil, err
+	}
+
+	if rpcResponse.Error != nil {
+		return json.Marshal(JsonRpcError{Code: rpcResponse.Error.Code, Message: rpcResponse.Error.Message})
+	}
+
+	normalized := &bytes.Buffer{}
+	if err := json.Compact(normalized, rpcResponse.Result); err != nil {
+		return nil, err
+	}
+
+	return normalized.Bytes(), nil
+}
+
+// SendRequestQuorum sends your request to quorum.Nodes distinct free nodes of the chain at the same time
+// it waits only for the first node, the request is sent to fewer nodes if the others are not free
+// it fails without sending the request if fewer than quorum.Agree nodes are free
+// the most common response is returned if at least quorum.Agree nodes responded it
+// answer of every node is reported in Metadata.Quorum of the response or of the error
+func (e *EzNode) SendRequestQuorum(
+	ctx context.Context,
+	chainId string,
+	request *http.Request,
+	quorum QuorumConfig,
+	options ...RequestOption,
+) (*Response, error) {
+	selectedChain := e.chains[chainId]
+	if selectedChain == nil {
+		return nil, errors.New(fmt.Sprintf("cannot find chain id %s", chainId))
+	}
+
+	if quorum.Nodes < 1 || quorum.Nodes > len(selectedChain.nodes) {
+		return nil, errors.New(fmt.Sprintf("quorum nodes must be between 1 and %d", len(selectedChain.nodes)))
+	}
+
+	if quorum.Agree < 1 || quorum.Agree > quorum.Nodes {
+		return nil, errors.New("quorum agree must be between 1 and quorum nodes")
+	}
+
+	var reqBody []byte
+	var err error
+	if request.Body != nil {
+		reqBody, err = io.ReadAll(request.Body)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	requestOpts := newRequestOptions(options)
+	query := nodeQuery{
+		excludeNodes: make(map[string]bool),
+		cost:         selectedChain.requestCost(requestOpts, request, reqBody),
+		maxWait:      requestOpts.maxWait,
+		class:        requestOpts.class,
+		requiredTags: selectedChain.requiredTags(request, reqBody),
+	}
+
+	metadata := ChainResponseMetadata{
+		ChainId:      selectedChain.id,
+		RequestedUrl: request.URL.String(),
+		Trace:        make([]NodeTrace, 0, quorum.Nodes),
+	}
+
+	firstNode, err := selectedChain.getFreeNode(ctx, query)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, newCanceledError(ctx.Err(), metadata)
+		}
+
+		if errors.Is(err, errNoMatchingNode) {
+			return nil, newNoMatchingNodeError(metadata)
+		}
+
+		errorMessage := fmt.Sprintf("'%s' chain is at full capacity", selectedChain.id)
+		metadata.Trace = append(metadata.Trace, NodeTrace{
+			Time:       time.Now(),
+			StatusCode: http.StatusTooManyRequests,
+			Err:        errors.New(errorMessage),
+		})
+		return nil, EzNodeError{
+			Message:  errorMessage,
+			Kind:     ErrorKindFullCapacity,
+			Metadata: metadata,
+		}
+	}
+
+	// other nodes are taken only if they are free now, waiting for them one by one would add up their wait durations
+	query.excludeNodes[firstNode.name] = true
+	selectedNodes := []*ChainNode{firstNode}
+	for len(selectedNodes) < quorum.Nodes {
+		selectedNode := selectedChain.findNode(query)
+		if selectedNode == nil {
+			break
+		}
+
+		query.excludeNodes[selectedNode.name] = true
+		selectedNodes = append(selectedNodes, selectedNode)
+	}
+
+	if len(selectedNodes) < quorum.Agree {
+		for _, selectedNode := range selectedNodes {
+			selectedChain.releaseNode(selectedNode, query.requestCost())
+		}
+
+		errorMessage := fmt.Sprintf(
+			"'%s' chain quorum is not reached, %d nodes are free but %d are required",
+			selectedChain.id,
+			len(selectedNodes),
+			quorum.Agree,
+		)
+		metadata.Trace = append(metadata.Trace, NodeTrace{
+			Time:       time.Now(),
+			StatusCode: http.StatusConflict,
+			Err:        errors.New(errorMessage),
+		})
+		return nil, EzNodeError{
+			Message:  errorMessage,
+			Kind:     ErrorKindQuorumNotReached,
+			Metadata: metadata,
+		}
+	}
+
+	results := make([]attemptResult, len(selectedNodes))
+	w := &sync.WaitGroup{}
+	for i, selectedNode := range selectedNodes {
+		w.Add(1)
+		go func(i int, selectedNode *ChainNode) {
+			defer w.Done()
+			results[i] = e.attempt(ctx, selectedChain, selectedNode, request, reqBody)
+		}(i, selectedNode)
+	}
+	w.Wait()
+
+	normalize := quorum.Normalize
+	if normalize == nil {
+		normalize = func(response *Response) ([]byte, error) {
+			return response.Body, nil
+		}
+	}
+
+	quorumMetadata := &QuorumMetadata{
+		Answers:    make([]QuorumAnswer, 0, len(results)),
+		Dissenters: make([]string, 0),
+	}
+	votes := make(map[string]int)
+	majority := -1
+	for i := range results {
+		metadata.Trace = append(metadata.Trace, results[i].trace)
+
+		answer := QuorumAnswer{
+			NodeName: results[i].node.name,
+			Err:      results[i].trace.Err,
+		}
+		if results[i].isValid {
+			answer.Answer, answer.Err = normalize(results[i].res)
+		}
+
+		if answer.Err == nil {
+			votes[string(answer.Answer)] += 1
+			if majority == -1 || votes[string(answer.Answer)] > votes[string(quorumMetadata.Answers[majority].Answer)] {
+				majority = i
+			}
+		}
+		quorumMetadata.Answers = append(quorumMetadata.Answers, answer)
+	}
+
+	// a split between answers is not a majority, so no answer is agreed when the top vote count is shared
+	split := false
+	if majority != -1 {
+		topVotes := votes[string(quorumMetadata.Answers[majority].Answer)]
+		for answer, count := range votes {
+			if count == topVotes && answer != string(quorumMetadata.Answers[majority].Answer) {
+				majority = -1
+				split = true
+				break
+			}
+		}
+	}
+
+	if majority != -1 {
+		quorumMetadata.Agreed = votes[string(quorumMetadata.Answers[majority].Answer)]
+	}
+	for i, answer := range quorumMetadata.Answers {
+		if majority == -1 || answer.Err != nil || (i != majority && !bytes.Equal(answer.Answer, quorumMetadata.Answers[majority].Answer)) {
+			quorumMetadata.Dissenters = append(quorumMetadata.Dissenters, answer.NodeName)
+		}
+	}
+	metadata.Quorum = quorumMetadata
+
+	if quorumMetadata.Agreed >= quorum.Agree {
+		res := results[majority].res
+		res.Metadata = metadata
+		return res, nil
+	}
+
+	if ctx.Err() != nil {
+		return nil, newCanceledError(ctx.Err(), metadata)
+	}
+
+	errorMessage := fmt.Sprintf(
+		"'%s' chain quorum is not reached, %d nodes agreed but %d are required",
+		selectedChain.id,
+		quorumMetadata.Agreed,
+		quorum.Agree,
+	)
+	if split {
+		errorMessage = fmt.Sprintf("'%s' chain quorum is not reached, answers of nodes are split evenly", selectedChain.id)
+	}
+	metadata.Trace = append(metadata.Trace, NodeTrace{
+		Time:       time.Now(),
+		StatusCode: http.StatusConflict,
+		Err:        errors.New(errorMessage),
+	})
+	return nil, EzNodeError{
+		Message:  errorMessage,
+		Kind:     ErrorKindQuorumNotReached,
+		Metadata: metadata,
+	}
+}
@@ -0,0 +1,220 @@
+package eznode
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSendRequestQuorum(t *testing.T) {
+	t.Parallel()
+
+	mockedApiCall := contextApiCall(func(ctx context.Context, request *http.Request) (*Response, error) {
+		body := `{"jsonrpc":"2.0","id":1,"result":"0x1"}`
+		switch request.URL.Host {
+		case "example2.com":
+			body = `{"jsonrpc": "2.0", "id": 1, "result": "0x1"}`
+		case "example3.com":
+			body = `{"jsonrpc":"2.0","id":1,"result":"0x2"}`
+		}
+
+		return &Response{
+			StatusCode: http.StatusOK,
+			Body:       []byte(body),
+			Headers:    &http.Header{},
+		}, nil
+	})
+
+	nodes := make([]*ChainNode, 0, 3)
+	for _, host := range []string{"example.com", "example2.com", "example3.com"} {
+		nodes = append(nodes, NewChainNode(NewChainNodeConfig{
+			Name: host,
+			Url:  "http://" + host,
+			Limit: ChainNodeLimit{
+				Count: 10,
+				Per:   1 * time.Minute,
+			},
+			RequestTimeout: 1 * time.Second,
+			Priority:       1,
+		}))
+	}
+
+	createdChain := NewChain(
+		NewChainConfig{
+			Id:    "test-chain",
+			Nodes: nodes,
+			CheckTickRate: CheckTick{
+				TickRate:         100 * time.Millisecond,
+				MaxCheckDuration: 200 * time.Millisecond,
+			},
+		},
+	)
+
+	ezNode := NewEzNode([]*Chain{createdChain}, WithApiClient(mockedApiCall))
+	newRequest := func() *http.Request {
+		request, _ := http.NewRequest("POST", "", bytes.NewBufferString(`{"jsonrpc":"2.0","id":1,"method":"eth_getBalance"}`))
+		return request
+	}
+
+	res, err := ezNode.SendRequestQuorum(context.Background(), "test-chain", newRequest(), QuorumConfig{
+		Nodes:     3,
+		Agree:     2,
+		Normalize: NormalizeJsonRpcResult,
+	})
+	assert.Nil(t, err)
+	normalized, _ := NormalizeJsonRpcResult(res)
+	assert.Equal(t, `"0x1"`, string(normalized))
+	assert.Equal(t, 3, len(res.Metadata.Trace))
+	assert.Equal(t, 2, res.Metadata.Quorum.Agreed)
+	assert.Equal(t, 3, len(res.Metadata.Quorum.Answers))
+	assert.Equal(t, []string{"example3.com"}, res.Metadata.Quorum.Dissenters)
+
+	_, err = ezNode.SendRequestQuorum(context.Background(), "test-chain", newRequest(), QuorumConfig{
+		Nodes: 3,
+		Agree: 2,
+	})
+	var ezNodeError EzNodeError
+	assert.True(t, errors.As(err, &ezNodeError))
+	assert.Equal(t, ErrorKindQuorumNotReached, ezNodeError.Kind)
+	assert.Equal(t, 0, ezNodeError.Metadata.Quorum.Agreed, "three different answers are a split")
+	assert.Equal(t, 3, len(ezNodeError.Metadata.Quorum.Dissenters))
+
+	_, err = ezNode.SendRequestQuorum(context.Background(), "test-chain", newRequest(), QuorumConfig{
+		Nodes: 4,
+		Agree: 2,
+	})
+	assert.NotNil(t, err)
+}
+
+func TestSendRequestQuorumWithoutWaiting(t *testing.T) {
+	t.Parallel()
+
+	mockedApiCall := contextApiCall(func(ctx context.Context, request *http.Request) (*Response, error) {
+		return &Response{
+			StatusCode: http.StatusOK,
+			Body:       []byte(`{"jsonrpc":"2.0","id":1,"result":"0x1"}`),
+			Headers:    &http.Header{},
+		}, nil
+	})
+
+	nodes := make([]*ChainNode, 0, 3)
+	for _, host := range []string{"example.com", "example2.com", "example3.com"} {
+		nodes = append(nodes, NewChainNode(NewChainNodeConfig{
+			Name: host,
+			Url:  "http://" + host,
+			Limit: ChainNodeLimit{
+				Count: 10,
+				Per:   1 * time.Minute,
+			},
+			RequestTimeout: 1 * time.Second,
+			Priority:       1,
+		}))
+	}
+
+	createdChain := NewChain(
+		NewChainConfig{
+			Id:    "test-chain",
+			Nodes: nodes,
+			CheckTickRate: CheckTick{
+				TickRate:         100 * time.Millisecond,
+				MaxCheckDuration: 1 * time.Second,
+			},
+			CostFunc: func(request *http.Request, body []byte) uint {
+				return 0
+			},
+		},
+	)
+
+	ezNode := NewEzNode([]*Chain{createdChain}, WithApiClient(mockedApiCall))
+	newRequest := func() *http.Request {
+		request, _ := http.NewRequest("POST", "", bytes.NewBufferString(`{"jsonrpc":"2.0","id":1,"method":"eth_getBalance"}`))
+		return request
+	}
+
+	createdChain.disableNode("example3.com")
+	startTime := time.Now()
+	res, err := ezNode.SendRequestQuorum(context.Background(), "test-chain", newRequest(), QuorumConfig{
+		Nodes: 3,
+		Agree: 2,
+	})
+	assert.Less(t, time.Since(startTime), 100*time.Millisecond, "should not wait for a disabled node")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, res.Metadata.Quorum.Agreed)
+	assert.Equal(t, 2, len(res.Metadata.Quorum.Answers))
+
+	createdChain.disableNode("example2.com")
+	startTime = time.Now()
+	_, err = ezNode.SendRequestQuorum(context.Background(), "test-chain", newRequest(), QuorumConfig{
+		Nodes: 3,
+		Agree: 2,
+	})
+	assert.Less(t, time.Since(startTime), 100*time.Millisecond, "should fail fast when quorum cannot be reached")
+	var ezNodeError EzNodeError
+	assert.True(t, errors.As(err, &ezNodeError))
+	assert.Equal(t, ErrorKindQuorumNotReached, ezNodeError.Kind)
+
+	stats := ezNode.GetStats()[0].Nodes[0]
+	assert.Equal(t, "example.com", stats.Name)
+	assert.Equal(t, uint(1), stats.CurrentHits, "unsent request should not use the node limit")
+	assert.Equal(t, int64(0), nodes[0].InFlight())
+}
+
+func TestSendRequestQuorumSplit(t *testing.T) {
+	t.Parallel()
+
+	mockedApiCall := contextApiCall(func(ctx context.Context, request *http.Request) (*Response, error) {
+		body := `{"jsonrpc":"2.0","id":1,"result":"0x1"}`
+		if request.URL.Host == "example3.com" || request.URL.Host == "example4.com" {
+			body = `{"jsonrpc":"2.0","id":1,"result":"0x2"}`
+		}
+
+		return &Response{
+			StatusCode: http.StatusOK,
+			Body:       []byte(body),
+			Headers:    &http.Header{},
+		}, nil
+	})
+
+	nodes := make([]*ChainNode, 0, 4)
+	for _, host := range []string{"example.com", "example2.com", "example3.com", "example4.com"} {
+		nodes = append(nodes, NewChainNode(NewChainNodeConfig{
+			Name: host,
+			Url:  "http://" + host,
+			Limit: ChainNodeLimit{
+				Count: 10,
+				Per:   1 * time.Minute,
+			},
+			RequestTimeout: 1 * time.Second,
+			Priority:       1,
+		}))
+	}
+
+	createdChain := NewChain(
+		NewChainConfig{
+			Id:    "test-chain",
+			Nodes: nodes,
+			CheckTickRate: CheckTick{
+				TickRate:         100 * time.Millisecond,
+				MaxCheckDuration: 200 * time.Millisecond,
+			},
+		},
+	)
+
+	ezNode := NewEzNode([]*Chain{createdChain}, WithApiClient(mockedApiCall))
+	request, _ := http.NewRequest("POST", "", bytes.NewBufferString(`{"jsonrpc":"2.0","id":1,"method":"eth_getBalance"}`))
+
+	_, err := ezNode.SendRequestQuorum(context.Background(), "test-chain", request, QuorumConfig{
+		Nodes: 4,
+		Agree: 2,
+	})
+	var ezNodeError EzNodeError
+	assert.True(t, errors.As(err, &ezNodeError), "an even split should not be a majority")
+	assert.Equal(t, ErrorKindQuorumNotReached, ezNodeError.Kind)
+	assert.Equal(t, 0, ezNodeError.Metadata.Quorum.Agreed)
+	assert.Equal(t, 4, len(ezNodeError.Metadata.Quorum.Dissenters))
+}
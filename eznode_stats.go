@@ -2,26 +2,44 @@ package eznode
 
 import (
 	"sync/atomic"
+	"time"
 )
 
 func (c *Chain) getStats() []ChainNodeStats {
-	c.mutex.RLock()
+	// circuit state may move from open to half-open, so write lock is needed
+	c.mutex.Lock()
+	now := time.Now()
 	nodeStats := make([]ChainNodeStats, 0)
 	for _, node := range c.nodes {
+		circuitState := node.circuitState(now)
+		throttledUntil := time.Time{}
+		if now.Before(node.throttledUntil) {
+			throttledUntil = node.throttledUntil
+		}
+
 		node.statsMutex.Lock()
 		nodeStats = append(nodeStats, ChainNodeStats{
-			Name:          node.name,
-			CurrentHits:   node.hits,
-			TotalHits:     atomic.LoadUint64(&node.totalHits),
-			ResponseStats: node.responseStats,
-			Limits:        node.limit.Count,
-			Priority:      node.priority,
-			Disabled:      node.disabled,
-			Fails:         node.fails,
+			Name:           node.name,
+			CurrentHits:    node.limiters[0].used(now),
+			TotalHits:      atomic.LoadUint64(&node.totalHits),
+			ResponseStats:  node.responseStats,
+			Limits:         node.limit.Count,
+			Priority:       node.priority,
+			Disabled:       node.disabled || circuitState == CircuitOpen || node.health.unhealthy,
+			Fails:          node.fails,
+			Latency:        node.latency,
+			ErrorRate:      node.errorRate,
+			CircuitState:   circuitState.String(),
+			Healthy:        !node.health.unhealthy,
+			Head:           node.head,
+			HeadLag:        c.headLag(node),
+			LimitStats:     node.limitStats(now),
+			ThrottledUntil: throttledUntil,
+			EffectiveLimit: node.limiters[0].limit.Count,
 		})
 		node.statsMutex.Unlock()
 	}
-	c.mutex.RUnlock()
+	c.mutex.Unlock()
 
 	return nodeStats
 }
@@ -32,8 +50,11 @@ func (e *EzNode) GetStats() []ChainStats {
 
 	for _, chain := range e.chains {
 		chainStats = append(chainStats, ChainStats{
-			Id:    chain.id,
-			Nodes: chain.getStats(),
+			Id:          chain.id,
+			Nodes:       chain.getStats(),
+			QueueLength: chain.queueLength(),
+			CacheHits:   atomic.LoadUint64(&chain.cacheHits),
+			CacheMisses: atomic.LoadUint64(&chain.cacheMisses),
 		})
 	}
 
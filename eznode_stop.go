@@ -0,0 +1,8 @@
+package eznode
+
+// Stop stops background jobs of all chains, such as health checks and head trackers
+func (e *EzNode) Stop() {
+	for _, chain := range e.chains {
+		chain.stop()
+	}
+}
@@ -0,0 +1,164 @@
+package eznode
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// SendRequestStream send your request to specific chain like SendRequest, but the response body is not buffered
+// the request is sent to another node if a node fails before the first byte of its body, so failover is still possible
+// after that, errors of reading the body, e.g. a body larger than MaxBodySize of the chain, are returned by Body.Read
+// hedging and ResponseValidator of the chain are not used for streamed requests
+func (e *EzNode) SendRequestStream(
+	ctx context.Context,
+	chainId string,
+	request *http.Request,
+	options ...RequestOption,
+) (*StreamResponse, error) {
+	selectedChain := e.chains[chainId]
+	if selectedChain == nil {
+		return nil, errors.New(fmt.Sprintf("cannot find chain id %s", chainId))
+	}
+
+	var reqBody []byte
+	var err error
+	if request.Body != nil {
+		reqBody, err = io.ReadAll(request.Body)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	requestOpts := newRequestOptions(options)
+	requestOpts.stream = true
+	result, err := e.sendRequest(ctx, selectedChain, request, reqBody, make(map[string]bool), requestOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.stream, nil
+}
+
+// streamBody closes the request of a streamed response when its body is closed
+type streamBody struct {
+	io.Reader
+	body   io.Closer
+	cancel context.CancelFunc
+}
+
+func (s *streamBody) Close() error {
+	defer s.cancel()
+	return s.body.Close()
+}
+
+// doStreamRequest sends the request by StreamApiCaller, or by ApiCaller if it is not implemented
+func (e *EzNode) doStreamRequest(ctx context.Context, request *http.Request) (*StreamResponse, error) {
+	if streamApiCaller, ok := e.apiCaller.(StreamApiCaller); ok {
+		return streamApiCaller.DoStreamRequest(ctx, request)
+	}
+
+	res, err := e.apiCaller.DoRequest(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StreamResponse{
+		StatusCode: res.StatusCode,
+		Body:       io.NopCloser(bytes.NewReader(res.Body)),
+		Headers:    res.Headers,
+	}, nil
+}
+
+// streamAttempt sends the request to the node and waits for the first byte of the body
+// RequestTimeout of the node applies until the first byte, the body is read within ctx
+func (e *EzNode) streamAttempt(
+	ctx context.Context,
+	selectedChain *Chain,
+	selectedNode *ChainNode,
+	request *http.Request,
+	reqBody []byte,
+) attemptResult {
+	clonedReq := request.Clone(context.Background())
+	clonedReq.Body = io.NopCloser(bytes.NewBuffer(reqBody))
+	clonedReq = selectedNode.middleware(clonedReq)
+	streamCtx, cancelStream := context.WithCancel(withMaxBodySize(ctx, selectedChain.maxBodySize))
+	firstByteTimeout := time.AfterFunc(selectedNode.requestTimeout, cancelStream)
+
+	startTime := time.Now()
+	stream, err := e.doStreamRequest(streamCtx, clonedReq)
+	var body *bufio.Reader
+	if err == nil {
+		body = bufio.NewReader(limitBody(stream.Body, selectedChain.maxBodySize))
+		if _, peekErr := body.Peek(1); peekErr != nil && peekErr != io.EOF {
+			stream.Body.Close()
+			stream, err = nil, peekErr
+		}
+	}
+	if !firstByteTimeout.Stop() {
+		if stream != nil {
+			stream.Body.Close()
+			stream = nil
+		}
+		err = errors.Join(context.DeadlineExceeded, err)
+	}
+	duration := time.Since(startTime)
+	atomic.AddInt64(&selectedNode.inFlight, -1)
+
+	// reportResult and collectMetric only look at status code and headers
+	var res *Response
+	if stream != nil {
+		res = &Response{
+			StatusCode: stream.StatusCode,
+			Headers:    stream.Headers,
+		}
+	}
+	isValid := isResponseValid(selectedChain.failureStatusCodes, res, err)
+	if isNodeResult(ctx, err) {
+		selectedChain.reportResult(selectedNode, res, err, isValid)
+		go collectMetric(selectedNode, res, err, isValid, duration)
+	} else {
+		selectedChain.reportAbandoned(selectedNode)
+	}
+
+	if isValid {
+		stream.Body = &streamBody{
+			Reader: body,
+			body:   stream.Body,
+			cancel: cancelStream,
+		}
+
+		return attemptResult{
+			node:    selectedNode,
+			stream:  stream,
+			isValid: true,
+			trace: NodeTrace{
+				Time:       time.Now(),
+				NodeName:   selectedNode.name,
+				StatusCode: stream.StatusCode,
+				Err:        nil,
+				Duration:   duration,
+			},
+		}
+	}
+
+	resStatusCode := 0
+	if stream != nil {
+		resStatusCode = stream.StatusCode
+		stream.Body.Close()
+	}
+	cancelStream()
+
+	return attemptResult{
+		node:  selectedNode,
+		res:   res,
+		err:   err,
+		trace: generateTrace(selectedNode.name, err, resStatusCode, duration),
+	}
+}
@@ -0,0 +1,185 @@
+package eznode
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type failingReader struct{}
+
+func (f failingReader) Read(p []byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
+
+type streamApiCall func(ctx context.Context, request *http.Request) (*StreamResponse, error)
+
+func (s streamApiCall) DoRequest(ctx context.Context, request *http.Request) (*Response, error) {
+	return nil, errors.New("not buffered")
+}
+
+func (s streamApiCall) DoStreamRequest(ctx context.Context, request *http.Request) (*StreamResponse, error) {
+	return s(ctx, request)
+}
+
+func TestSendRequestStream(t *testing.T) {
+	t.Parallel()
+
+	largeBody := bytes.Repeat([]byte("0123456789"), 100000)
+	mockedApiCall := streamApiCall(func(ctx context.Context, request *http.Request) (*StreamResponse, error) {
+		var body io.Reader = bytes.NewReader(largeBody)
+		if request.URL.Host == "example.com" {
+			body = failingReader{}
+		}
+
+		return &StreamResponse{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(body),
+			Headers:    &http.Header{},
+		}, nil
+	})
+
+	chainNode1 := NewChainNode(NewChainNodeConfig{
+		Name: "Node 1",
+		Url:  "http://example.com",
+		Limit: ChainNodeLimit{
+			Count: 10,
+			Per:   1 * time.Minute,
+		},
+		RequestTimeout: 1 * time.Second,
+		Priority:       2,
+	})
+
+	chainNode2 := NewChainNode(NewChainNodeConfig{
+		Name: "Node 2",
+		Url:  "http://example2.com",
+		Limit: ChainNodeLimit{
+			Count: 10,
+			Per:   1 * time.Minute,
+		},
+		RequestTimeout: 1 * time.Second,
+		Priority:       1,
+	})
+
+	createdChain := NewChain(
+		NewChainConfig{
+			Id: "test-chain",
+			Nodes: []*ChainNode{
+				chainNode1,
+				chainNode2,
+			},
+			CheckTickRate: CheckTick{
+				TickRate:         100 * time.Millisecond,
+				MaxCheckDuration: 200 * time.Millisecond,
+			},
+			RetryCount: 1,
+		},
+	)
+
+	ezNode := NewEzNode([]*Chain{createdChain}, WithApiClient(mockedApiCall))
+	request, _ := http.NewRequest("POST", "", nil)
+
+	res, err := ezNode.SendRequestStream(context.Background(), "test-chain", request)
+	assert.Nil(t, err)
+	defer res.Body.Close()
+	assert.Equal(t, 2, len(res.Metadata.Trace))
+	assert.Equal(t, chainNode1.name, res.Metadata.Trace[0].NodeName)
+	assert.EqualError(t, res.Metadata.Trace[0].Err, "connection reset")
+	assert.Equal(t, chainNode2.name, res.Metadata.Trace[1].NodeName)
+
+	body, err := io.ReadAll(res.Body)
+	assert.Nil(t, err)
+	assert.Equal(t, largeBody, body)
+}
+
+func TestMaxBodySize(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.Write(bytes.Repeat([]byte("a"), 1000))
+	}))
+	defer server.Close()
+
+	chainNode1 := NewChainNode(NewChainNodeConfig{
+		Name: "Node 1",
+		Url:  server.URL,
+		Limit: ChainNodeLimit{
+			Count: 10,
+			Per:   1 * time.Minute,
+		},
+		RequestTimeout: 1 * time.Second,
+		Priority:       1,
+	})
+
+	createdChain := NewChain(
+		NewChainConfig{
+			Id: "test-chain",
+			Nodes: []*ChainNode{
+				chainNode1,
+			},
+			CheckTickRate: CheckTick{
+				TickRate:         100 * time.Millisecond,
+				MaxCheckDuration: 200 * time.Millisecond,
+			},
+			RetryCount:  2,
+			MaxBodySize: 100,
+		},
+	)
+
+	ezNode := NewEzNode([]*Chain{createdChain})
+
+	request, _ := http.NewRequest("GET", "", nil)
+	_, err := ezNode.SendRequest(context.Background(), "test-chain", request)
+	var ezNodeError EzNodeError
+	assert.True(t, errors.As(err, &ezNodeError))
+	assert.Equal(t, ErrorKindBodyTooLarge, ezNodeError.Kind)
+	assert.ErrorIs(t, err, errBodyTooLarge)
+	assert.Equal(t, 1, len(ezNodeError.Metadata.Trace), "too large body should not be retried")
+
+	request, _ = http.NewRequest("GET", "", nil)
+	res, err := ezNode.SendRequestStream(context.Background(), "test-chain", request)
+	assert.Nil(t, err)
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	assert.ErrorIs(t, err, errBodyTooLarge)
+	assert.Equal(t, 100, len(body))
+	assert.Equal(t, 0.0, chainNode1.ErrorRate(), "too large body should not count as failure")
+}
+
+func TestStreamOutlivesClientTimeout(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.Write([]byte("first "))
+		writer.(http.Flusher).Flush()
+		time.Sleep(300 * time.Millisecond)
+		writer.Write([]byte("last"))
+	}))
+	defer server.Close()
+
+	client := createHttpClient()
+	client.Timeout = 100 * time.Millisecond
+	apiCaller := &apiCallerClient{
+		client:       client,
+		streamClient: createStreamHttpClient(client),
+	}
+
+	request, _ := http.NewRequest("GET", server.URL, nil)
+	res, err := apiCaller.DoStreamRequest(context.Background(), request)
+	assert.Nil(t, err)
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	assert.Nil(t, err, "stream should not be cut off by the total timeout of buffered requests")
+	assert.Equal(t, "first last", string(body))
+
+	_, err = apiCaller.DoRequest(context.Background(), request)
+	assert.NotNil(t, err, "buffered requests should keep the total timeout")
+}
@@ -3,8 +3,10 @@ package eznode
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -324,6 +326,69 @@ func TestNoMoreTryWhenCheckedAll(t *testing.T) {
 	assert.Equal(t, 3, retryCount)
 }
 
+func TestRetryCountBoundsAttempts(t *testing.T) {
+	t.Parallel()
+
+	for _, testCase := range []struct {
+		retryCount       int
+		strictRetryCount bool
+		attempts         int64
+	}{
+		{retryCount: 0, attempts: 1},
+		{retryCount: 2, attempts: 5},
+		{retryCount: 2, strictRetryCount: true, attempts: 3},
+	} {
+		var attempts int64
+		mockedApiCall := mockApiCall{
+			returnFunc: func(request *http.Request) (*Response, error) {
+				atomic.AddInt64(&attempts, 1)
+				return &Response{
+					StatusCode: http.StatusServiceUnavailable,
+					Headers:    &http.Header{},
+				}, nil
+			},
+			validateFunc: func(request *http.Request) {
+			},
+		}
+
+		nodes := make([]*ChainNode, 0, 5)
+		for i := 1; i <= 5; i++ {
+			nodes = append(nodes, NewChainNode(NewChainNodeConfig{
+				Name: fmt.Sprintf("Node %d", i),
+				Url:  fmt.Sprintf("http://example%d.com", i),
+				Limit: ChainNodeLimit{
+					Count: 10,
+					Per:   1 * time.Minute,
+				},
+				RequestTimeout: 1 * time.Second,
+				Priority:       1,
+			}))
+		}
+
+		createdChain := NewChain(
+			NewChainConfig{
+				Id:    "test-chain",
+				Nodes: nodes,
+				CheckTickRate: CheckTick{
+					TickRate:         100 * time.Millisecond,
+					MaxCheckDuration: 200 * time.Millisecond,
+				},
+				RetryCount:       testCase.retryCount,
+				StrictRetryCount: testCase.strictRetryCount,
+			},
+		)
+
+		ezNode := NewEzNode([]*Chain{createdChain}, WithApiClient(mockedApiCall))
+		request, _ := http.NewRequest("GET", "/", nil)
+		_, err := ezNode.SendRequest(context.Background(), "test-chain", request)
+
+		var ezNodeError EzNodeError
+		assert.True(t, errors.As(err, &ezNodeError))
+		assert.Equal(t, ErrorKindMaxRetries, ezNodeError.Kind)
+		assert.Equal(t, testCase.attempts, atomic.LoadInt64(&attempts), "should try every node unless StrictRetryCount is set")
+	}
+}
+
 func TestLockAndReleaseResource(t *testing.T) {
 	t.Parallel()
 
@@ -374,18 +439,18 @@ func TestLockAndReleaseResource(t *testing.T) {
 	res, err := ezNode.SendRequest(context.Background(), "test-chain", request)
 	assert.Nil(t, err)
 	assert.Equal(t, 200, res.StatusCode)
-	assert.Equal(t, uint(1), ezNode.chains["test-chain"].nodes[0].hits)
+	assert.Equal(t, uint(1), ezNode.chains["test-chain"].nodes[0].hits())
 
 	res, err = ezNode.SendRequest(context.Background(), "test-chain", request)
 	assert.NotNil(t, err)
-	assert.Equal(t, uint(1), ezNode.chains["test-chain"].nodes[0].hits)
+	assert.Equal(t, uint(1), ezNode.chains["test-chain"].nodes[0].hits())
 
 	time.Sleep(2 * time.Second)
-	assert.Equal(t, uint(0), ezNode.chains["test-chain"].nodes[0].hits)
+	assert.Equal(t, uint(0), ezNode.chains["test-chain"].nodes[0].hits())
 	res, err = ezNode.SendRequest(context.Background(), "test-chain", request)
 	assert.Nil(t, err)
 	assert.Equal(t, 200, res.StatusCode)
-	assert.Equal(t, uint(1), ezNode.chains["test-chain"].nodes[0].hits)
+	assert.Equal(t, uint(1), ezNode.chains["test-chain"].nodes[0].hits())
 }
 
 func TestConcurrentRequests(t *testing.T) {
@@ -484,12 +549,12 @@ func TestConcurrentRequests(t *testing.T) {
 		}()
 	}
 
-	for _, node := range ezNode.chains["test-chain"].nodes {
-		assert.True(t, node.hits <= node.limit.Count)
+	for _, nodeStats := range ezNode.chains["test-chain"].getStats() {
+		assert.True(t, nodeStats.CurrentHits <= nodeStats.Limits)
 	}
 	w.Wait()
 
-	for _, node := range ezNode.chains["test-chain"].nodes {
-		assert.True(t, node.hits <= node.limit.Count)
+	for _, nodeStats := range ezNode.chains["test-chain"].getStats() {
+		assert.True(t, nodeStats.CurrentHits <= nodeStats.Limits)
 	}
 }
@@ -0,0 +1,111 @@
+package eznode
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// HeadExtractor knows how to ask a node about its latest block
+type HeadExtractor interface {
+	// Request creates the request which asks for the latest block
+	// Note: the request should not have host, schema and port
+	Request() (*http.Request, error)
+	// Extract reads the latest block number from the response
+	Extract(response *Response) (uint64, error)
+}
+
+// EthereumHeadExtractor reads the latest block of EVM nodes by eth_blockNumber
+type EthereumHeadExtractor struct{}
+
+func (e EthereumHeadExtractor) Request() (*http.Request, error) {
+	return newJsonRpcProbeRequest(`{"jsonrpc":"2.0","id":1,"method":"eth_blockNumber","params":[]}`)
+}
+
+func (e EthereumHeadExtractor) Extract(response *Response) (uint64, error) {
+	var result string
+	if err := decodeJsonRpcProbeResult(response, &result); err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseUint(strings.TrimPrefix(result, "0x"), 16, 64)
+}
+
+// BitcoinHeadExtractor reads the latest block of bitcoin nodes by getblockcount
+type BitcoinHeadExtractor struct{}
+
+func (e BitcoinHeadExtractor) Request() (*http.Request, error) {
+	return newJsonRpcProbeRequest(`{"jsonrpc":"1.0","id":"eznode","method":"getblockcount","params":[]}`)
+}
+
+func (e BitcoinHeadExtractor) Extract(response *Response) (uint64, error) {
+	var result uint64
+	if err := decodeJsonRpcProbeResult(response, &result); err != nil {
+		return 0, err
+	}
+
+	return result, nil
+}
+
+// CosmosHeadExtractor reads the latest block of cosmos (tendermint/cometbft) nodes by /status
+type CosmosHeadExtractor struct{}
+
+func (e CosmosHeadExtractor) Request() (*http.Request, error) {
+	return http.NewRequest(http.MethodGet, "/status", nil)
+}
+
+func (e CosmosHeadExtractor) Extract(response *Response) (uint64, error) {
+	type syncInfo struct {
+		SyncInfo struct {
+			LatestBlockHeight string `json:"latest_block_height"`
+		} `json:"sync_info"`
+	}
+
+	var status struct {
+		syncInfo
+		Result *syncInfo `json:"result"`
+	}
+	if err := json.Unmarshal(response.Body, &status); err != nil {
+		return 0, err
+	}
+
+	height := status.SyncInfo.LatestBlockHeight
+	if status.Result != nil {
+		height = status.Result.SyncInfo.LatestBlockHeight
+	}
+	if height == "" {
+		return 0, errors.New("latest block height is not in the response")
+	}
+
+	return strconv.ParseUint(height, 10, 64)
+}
+
+func newJsonRpcProbeRequest(body string) (*http.Request, error) {
+	request, err := http.NewRequest(http.MethodPost, "", bytes.NewBufferString(body))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	return request, nil
+}
+
+func decodeJsonRpcProbeResult(response *Response, result any) error {
+	var envelope struct {
+		Result json.RawMessage `json:"result"`
+		Error  json.RawMessage `json:"error"`
+	}
+	if err := json.Unmarshal(response.Body, &envelope); err != nil {
+		return err
+	}
+
+	if len(envelope.Error) > 0 && string(envelope.Error) != "null" {
+		return errors.New(fmt.Sprintf("node responded with error %s", envelope.Error))
+	}
+
+	return json.Unmarshal(envelope.Result, result)
+}
@@ -0,0 +1,17 @@
+package eznode
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// JsonRpcError is the error object of a JSON-RPC 2.0 response
+type JsonRpcError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+func (e *JsonRpcError) Error() string {
+	return fmt.Sprintf("json-rpc error %d: %s", e.Code, e.Message)
+}
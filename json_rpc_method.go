@@ -0,0 +1,39 @@
+package eznode
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// jsonRpcMethods returns methods of a JSON-RPC request or batch request body
+// it returns nil if body is not JSON-RPC
+func jsonRpcMethods(body []byte) []string {
+	type call struct {
+		Method string `json:"method"`
+	}
+
+	trimmedBody := bytes.TrimSpace(body)
+	if len(trimmedBody) == 0 {
+		return nil
+	}
+
+	if trimmedBody[0] == '[' {
+		var calls []call
+		if err := json.Unmarshal(trimmedBody, &calls); err != nil {
+			return nil
+		}
+
+		methods := make([]string, 0, len(calls))
+		for _, c := range calls {
+			methods = append(methods, c.Method)
+		}
+		return methods
+	}
+
+	var c call
+	if err := json.Unmarshal(trimmedBody, &c); err != nil || c.Method == "" {
+		return nil
+	}
+
+	return []string{c.Method}
+}
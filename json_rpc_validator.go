@@ -0,0 +1,106 @@
+package eznode
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"strings"
+)
+
+// DefaultJsonRpcRetryableCodes is the default list of JSON-RPC error codes which recognized as failure
+var DefaultJsonRpcRetryableCodes = []int{
+	-32005, // limit exceeded
+	-32603, // internal error
+}
+
+// DefaultJsonRpcRetryableMessages is the default list of JSON-RPC error messages which recognized as failure
+var DefaultJsonRpcRetryableMessages = []string{
+	"header not found",
+	"missing trie node",
+	"rate limit",
+	"limit exceeded",
+	"too many requests",
+	"timeout",
+}
+
+type JsonRpcValidatorConfig struct {
+	// RetryableCodes is the list of JSON-RPC error codes which recognized as failure
+	RetryableCodes []int
+	// RetryableMessages is the list of case-insensitive substrings of JSON-RPC error messages which recognized as failure
+	RetryableMessages []string
+}
+
+// JsonRpcValidator fails responses which contain a retryable JSON-RPC error
+// other JSON-RPC errors, e.g. execution reverted, are answers of the request and returned to the caller
+type JsonRpcValidator struct {
+	retryableCodes    []int
+	retryableMessages []string
+}
+
+// NewJsonRpcValidator creates new JsonRpcValidator
+// If neither RetryableCodes nor RetryableMessages is specified, default lists are used
+func NewJsonRpcValidator(config JsonRpcValidatorConfig) *JsonRpcValidator {
+	if config.RetryableCodes == nil && config.RetryableMessages == nil {
+		config.RetryableCodes = DefaultJsonRpcRetryableCodes
+		config.RetryableMessages = DefaultJsonRpcRetryableMessages
+	}
+
+	retryableMessages := make([]string, 0, len(config.RetryableMessages))
+	for _, message := range config.RetryableMessages {
+		retryableMessages = append(retryableMessages, strings.ToLower(message))
+	}
+
+	return &JsonRpcValidator{
+		retryableCodes:    config.RetryableCodes,
+		retryableMessages: retryableMessages,
+	}
+}
+
+// Validate returns the first retryable error of a JSON-RPC response or batch response
+// bodies which are not JSON-RPC are valid
+func (v *JsonRpcValidator) Validate(response *Response) error {
+	type result struct {
+		Error *JsonRpcError `json:"error"`
+	}
+
+	trimmedBody := bytes.TrimSpace(response.Body)
+	if len(trimmedBody) == 0 {
+		return nil
+	}
+
+	var results []result
+	if trimmedBody[0] == '[' {
+		if err := json.Unmarshal(trimmedBody, &results); err != nil {
+			return nil
+		}
+	} else {
+		var r result
+		if err := json.Unmarshal(trimmedBody, &r); err != nil {
+			return nil
+		}
+		results = []result{r}
+	}
+
+	for _, r := range results {
+		if r.Error != nil && v.isRetryable(r.Error) {
+			return r.Error
+		}
+	}
+
+	return nil
+}
+
+func (v *JsonRpcValidator) isRetryable(rpcError *JsonRpcError) bool {
+	if slices.Contains(v.retryableCodes, rpcError.Code) {
+		return true
+	}
+
+	message := strings.ToLower(rpcError.Message)
+	for _, retryableMessage := range v.retryableMessages {
+		if strings.Contains(message, retryableMessage) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,103 @@
+package eznode
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJsonRpcValidator(t *testing.T) {
+	t.Parallel()
+
+	validator := NewJsonRpcValidator(JsonRpcValidatorConfig{})
+	validate := func(body string) error {
+		return validator.Validate(&Response{StatusCode: http.StatusOK, Body: []byte(body)})
+	}
+
+	assert.Nil(t, validate(`{"jsonrpc":"2.0","id":1,"result":"0x10"}`))
+	assert.Nil(t, validate(`not json`))
+	assert.Nil(t, validate(`{"jsonrpc":"2.0","id":1,"error":{"code":3,"message":"execution reverted"}}`))
+
+	err := validate(`{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"Header not found"}}`)
+	var rpcError *JsonRpcError
+	assert.True(t, errors.As(err, &rpcError))
+	assert.Equal(t, -32000, rpcError.Code)
+
+	assert.NotNil(t, validate(`{"jsonrpc":"2.0","id":1,"error":{"code":-32005,"message":"slow down"}}`))
+	assert.NotNil(t, validate(`[{"id":1,"result":"0x1"},{"id":2,"error":{"code":-32603,"message":"internal"}}]`))
+
+	customValidator := NewJsonRpcValidator(JsonRpcValidatorConfig{RetryableCodes: []int{3}})
+	assert.NotNil(t, customValidator.Validate(&Response{Body: []byte(`{"error":{"code":3,"message":"execution reverted"}}`)}))
+	assert.Nil(t, customValidator.Validate(&Response{Body: []byte(`{"error":{"code":-32000,"message":"header not found"}}`)}))
+}
+
+func TestResponseValidatorFailover(t *testing.T) {
+	t.Parallel()
+
+	mockedApiCall := contextApiCall(func(ctx context.Context, request *http.Request) (*Response, error) {
+		body := `{"jsonrpc":"2.0","id":1,"result":"0x10"}`
+		if request.URL.Host == "example.com" {
+			body = `{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"header not found"}}`
+		}
+
+		return &Response{
+			StatusCode: http.StatusOK,
+			Body:       []byte(body),
+			Headers:    &http.Header{},
+		}, nil
+	})
+
+	chainNode1 := NewChainNode(NewChainNodeConfig{
+		Name: "Node 1",
+		Url:  "http://example.com",
+		Limit: ChainNodeLimit{
+			Count: 10,
+			Per:   1 * time.Minute,
+		},
+		RequestTimeout: 1 * time.Second,
+		Priority:       2,
+	})
+
+	chainNode2 := NewChainNode(NewChainNodeConfig{
+		Name: "Node 2",
+		Url:  "http://example2.com",
+		Limit: ChainNodeLimit{
+			Count: 10,
+			Per:   1 * time.Minute,
+		},
+		RequestTimeout: 1 * time.Second,
+		Priority:       1,
+	})
+
+	createdChain := NewChain(
+		NewChainConfig{
+			Id: "test-chain",
+			Nodes: []*ChainNode{
+				chainNode1,
+				chainNode2,
+			},
+			CheckTickRate: CheckTick{
+				TickRate:         100 * time.Millisecond,
+				MaxCheckDuration: 200 * time.Millisecond,
+			},
+			RetryCount:        1,
+			ResponseValidator: NewJsonRpcValidator(JsonRpcValidatorConfig{}),
+		},
+	)
+
+	ezNode := NewEzNode([]*Chain{createdChain}, WithApiClient(mockedApiCall))
+	request, _ := http.NewRequest("POST", "/", nil)
+
+	res, err := ezNode.SendRequest(context.Background(), "test-chain", request)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"jsonrpc":"2.0","id":1,"result":"0x10"}`, string(res.Body))
+	assert.Equal(t, 2, len(res.Metadata.Trace))
+	assert.Equal(t, chainNode1.name, res.Metadata.Trace[0].NodeName)
+	assert.Equal(t, http.StatusOK, res.Metadata.Trace[0].StatusCode)
+	assert.EqualError(t, res.Metadata.Trace[0].Err, "json-rpc error -32000: header not found")
+	assert.Equal(t, chainNode2.name, res.Metadata.Trace[1].NodeName)
+}
@@ -10,16 +10,37 @@ import (
 type Option func(*EzNode)
 
 // NewEzNode creates a new EzNode
+// It starts background jobs of the chains, call Stop to stop them
+// it calls log.Fatal if an option is invalid, use NewEzNodeE to handle the error
 func NewEzNode(chains []*Chain, options ...Option) *EzNode {
+	ezNode, err := NewEzNodeE(chains, options...)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return ezNode
+}
+
+// NewEzNodeE creates a new EzNode like NewEzNode
+// it returns *ValidationError if a chain or an option is invalid, background jobs are not started then
+func NewEzNodeE(chains []*Chain, options ...Option) (*EzNode, error) {
+	errs := fieldErrors{}
 	chainHashMap := make(map[string]*Chain)
-	for _, userChain := range chains {
+	for i, userChain := range chains {
+		if userChain == nil {
+			errs.add(indexedField("chains", i), "cannot be nil")
+			continue
+		}
+
 		chainHashMap[userChain.id] = userChain
 	}
 
+	httpClient := createHttpClient()
 	ezNode := &EzNode{
 		chains: chainHashMap,
 		apiCaller: &apiCallerClient{
-			client: createHttpClient(),
+			client:       httpClient,
+			streamClient: createStreamHttpClient(httpClient),
 		},
 		syncStorage: syncStorage{
 			interval: 60 * time.Second,
@@ -28,13 +49,26 @@ func NewEzNode(chains []*Chain, options ...Option) *EzNode {
 			isRun:    false,
 			mutex:    &sync.Mutex{},
 		},
+		coalescing: &coalescing{
+			calls: make(map[string]*coalescedCall),
+			mutex: &sync.Mutex{},
+		},
 	}
 
 	for _, option := range options {
 		option(ezNode)
 	}
 
-	return ezNode
+	errs = append(errs, ezNode.optionErrors...)
+	if err := errs.err(); err != nil {
+		return nil, err
+	}
+
+	for _, chain := range ezNode.chains {
+		chain.start(ezNode.apiCaller)
+	}
+
+	return ezNode, nil
 }
 
 // WithApiClient sets the api client
@@ -45,14 +79,16 @@ func WithApiClient(apiCaller ApiCaller) Option {
 }
 
 // WithSyncInterval sets the sync interval for calling sync stats function
+// interval must be greater than 0, less than 10 seconds is not recommended
 func WithSyncInterval(
 	interval time.Duration,
 ) Option {
-	if interval <= 0 {
-		log.Fatal("save interval cannot be less than 0 [less than 10 seconds is not recommended]")
-	}
-
 	return func(ezNode *EzNode) {
+		if interval <= 0 {
+			ezNode.optionErrors.add("syncInterval", "must be greater than 0")
+			return
+		}
+
 		ezNode.syncStorage.interval = interval
 	}
 }
@@ -0,0 +1,44 @@
+package eznode
+
+// PriorityClass determines how a request competes with other requests of the chain for node capacity
+type PriorityClass int
+
+const (
+	// PriorityNormal is the class of requests which do not set a class
+	PriorityNormal PriorityClass = iota
+	// PriorityCritical requests are served before other requests which wait for a free node
+	PriorityCritical
+	// PriorityBackground requests are served after other requests which wait for a free node
+	// and can only use BackgroundCapacity of node limits
+	PriorityBackground
+)
+
+func (p PriorityClass) String() string {
+	switch p {
+	case PriorityCritical:
+		return "critical"
+	case PriorityBackground:
+		return "background"
+	default:
+		return "normal"
+	}
+}
+
+// rank orders classes in the wait queue, lower rank is served first
+func (p PriorityClass) rank() int {
+	switch p {
+	case PriorityCritical:
+		return 0
+	case PriorityBackground:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// WithPriorityClass sets the priority class of the request, PriorityNormal is used by default
+func WithPriorityClass(class PriorityClass) RequestOption {
+	return func(requestOpts *requestOptions) {
+		requestOpts.class = class
+	}
+}
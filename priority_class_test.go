@@ -0,0 +1,145 @@
+package eznode
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPriorityClassOrdersWaitQueue(t *testing.T) {
+	t.Parallel()
+
+	chainNode1 := NewChainNode(NewChainNodeConfig{
+		Name: "Node 1",
+		Url:  "http://example.com",
+		Limit: ChainNodeLimit{
+			Count: 1,
+			Per:   100 * time.Millisecond,
+		},
+		RequestTimeout: 1 * time.Second,
+		Priority:       1,
+	})
+
+	createdChain := NewChain(
+		NewChainConfig{
+			Id: "test chain",
+			Nodes: []*ChainNode{
+				chainNode1,
+			},
+			CheckTickRate: CheckTick{
+				TickRate:         1 * time.Second,
+				MaxCheckDuration: 2 * time.Second,
+			},
+		},
+	)
+
+	foundNode, _ := createdChain.getFreeNode(context.Background(), nodeQuery{})
+	assert.NotNil(t, foundNode)
+
+	mutex := &sync.Mutex{}
+	order := make([]PriorityClass, 0)
+	w := &sync.WaitGroup{}
+	for _, class := range []PriorityClass{PriorityBackground, PriorityNormal, PriorityCritical} {
+		w.Add(1)
+		go func(class PriorityClass) {
+			defer w.Done()
+			foundNode, err := createdChain.getFreeNode(context.Background(), nodeQuery{class: class})
+			assert.Nil(t, err)
+			assert.NotNil(t, foundNode)
+
+			mutex.Lock()
+			order = append(order, class)
+			mutex.Unlock()
+		}(class)
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	w.Wait()
+	assert.Equal(t, []PriorityClass{PriorityCritical, PriorityNormal, PriorityBackground}, order)
+}
+
+func TestBackgroundCapacity(t *testing.T) {
+	t.Parallel()
+
+	chainNode1 := NewChainNode(NewChainNodeConfig{
+		Name: "Node 1",
+		Url:  "http://example.com",
+		Limit: ChainNodeLimit{
+			Count: 10,
+			Per:   1 * time.Minute,
+		},
+		RequestTimeout: 1 * time.Second,
+		Priority:       1,
+	})
+
+	createdChain := NewChain(
+		NewChainConfig{
+			Id: "test chain",
+			Nodes: []*ChainNode{
+				chainNode1,
+			},
+			CheckTickRate: CheckTick{
+				TickRate:         100 * time.Millisecond,
+				MaxCheckDuration: 200 * time.Millisecond,
+			},
+			BackgroundCapacity: 0.3,
+		},
+	)
+
+	for i := 0; i < 3; i++ {
+		foundNode, err := createdChain.getFreeNode(context.Background(), nodeQuery{class: PriorityBackground})
+		assert.Nil(t, err)
+		assert.NotNil(t, foundNode)
+	}
+
+	foundNode, err := createdChain.getFreeNode(context.Background(), nodeQuery{class: PriorityBackground})
+	assert.Nil(t, foundNode)
+	assert.ErrorIs(t, err, errNoFreeNode)
+
+	for i := 0; i < 7; i++ {
+		foundNode, err := createdChain.getFreeNode(context.Background(), nodeQuery{class: PriorityCritical})
+		assert.Nil(t, err)
+		assert.NotNil(t, foundNode)
+	}
+	assert.Equal(t, uint(10), chainNode1.hits())
+}
+
+func TestBackgroundCapacityOnLowLimit(t *testing.T) {
+	t.Parallel()
+
+	chainNode1 := NewChainNode(NewChainNodeConfig{
+		Name: "Node 1",
+		Url:  "http://example.com",
+		Limit: ChainNodeLimit{
+			Count: 1,
+			Per:   1 * time.Minute,
+		},
+		RequestTimeout: 1 * time.Second,
+		Priority:       1,
+	})
+
+	createdChain := NewChain(
+		NewChainConfig{
+			Id: "test chain",
+			Nodes: []*ChainNode{
+				chainNode1,
+			},
+			CheckTickRate: CheckTick{
+				TickRate:         100 * time.Millisecond,
+				MaxCheckDuration: 200 * time.Millisecond,
+			},
+			BackgroundCapacity: 0.5,
+		},
+	)
+
+	foundNode, err := createdChain.getFreeNode(context.Background(), nodeQuery{class: PriorityBackground})
+	assert.Nil(t, err)
+	assert.NotNil(t, foundNode, "background share should be at least one request")
+
+	foundNode, err = createdChain.getFreeNode(context.Background(), nodeQuery{class: PriorityBackground})
+	assert.Nil(t, foundNode)
+	assert.ErrorIs(t, err, errNoFreeNode)
+}
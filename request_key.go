@@ -0,0 +1,94 @@
+package eznode
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// jsonRpcCall is a JSON-RPC request which is parsed without decoding its params
+type jsonRpcCall struct {
+	Id     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+}
+
+// parseJsonRpcCall parses a single JSON-RPC request, it returns false for batches and other bodies
+func parseJsonRpcCall(body []byte) (jsonRpcCall, bool) {
+	var call jsonRpcCall
+	trimmedBody := bytes.TrimSpace(body)
+	if len(trimmedBody) == 0 || trimmedBody[0] != '{' {
+		return call, false
+	}
+
+	if err := json.Unmarshal(trimmedBody, &call); err != nil || call.Method == "" {
+		return call, false
+	}
+
+	return call, true
+}
+
+// requestKey identifies identical requests of a chain
+// id of a JSON-RPC request is ignored, so calls which only differ by id have the same key
+func requestKey(chainId string, request *http.Request, body []byte) string {
+	hash := sha256.New()
+	for _, part := range []string{chainId, request.Method, request.URL.String()} {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+
+	if call, ok := parseJsonRpcCall(body); ok {
+		hash.Write([]byte(call.Method))
+		hash.Write([]byte{0})
+
+		params := &bytes.Buffer{}
+		if err := json.Compact(params, call.Params); err != nil {
+			params.Write(call.Params)
+		}
+		hash.Write(params.Bytes())
+	} else {
+		hash.Write(body)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// withIncludeNodes adds nodes which a request is restricted to to its key, so responses of other nodes are not shared with it
+func withIncludeNodes(key string, includeNodeList []string) string {
+	if len(includeNodeList) == 0 {
+		return key
+	}
+
+	includeNodes := slices.Clone(includeNodeList)
+	slices.Sort(includeNodes)
+	return key + "|" + strings.Join(includeNodes, ",")
+}
+
+// withJsonRpcId replaces id of a JSON-RPC response body by id of the request which receives it
+// body is returned as is if it is not a JSON-RPC response or id is empty
+func withJsonRpcId(body []byte, id json.RawMessage) []byte {
+	if len(id) == 0 {
+		return body
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return body
+	}
+
+	if _, ok := fields["id"]; !ok {
+		return body
+	}
+
+	fields["id"] = id
+	replaced, err := json.Marshal(fields)
+	if err != nil {
+		return body
+	}
+
+	return replaced
+}
@@ -0,0 +1,39 @@
+package eznode
+
+import "time"
+
+// RequestOption is a functional parameter for SendRequest and SendRequestSpecific
+type RequestOption func(*requestOptions)
+
+type requestOptions struct {
+	cost     uint
+	hedge    *HedgeConfig
+	maxWait  time.Duration
+	class    PriorityClass
+	coalesce bool
+	stream   bool
+}
+
+func newRequestOptions(options []RequestOption) *requestOptions {
+	requestOpts := &requestOptions{}
+	for _, option := range options {
+		option(requestOpts)
+	}
+
+	return requestOpts
+}
+
+// WithCost sets how much of the node limits the request consumes, it overrides CostFunc of the chain
+// a request which costs more than a node limit burst is never sent to that node
+func WithCost(cost uint) RequestOption {
+	return func(requestOpts *requestOptions) {
+		requestOpts.cost = cost
+	}
+}
+
+// WithMaxWait sets how long the request waits for a free node, it overrides MaxCheckDuration of the chain
+func WithMaxWait(maxWait time.Duration) RequestOption {
+	return func(requestOpts *requestOptions) {
+		requestOpts.maxWait = maxWait
+	}
+}
@@ -0,0 +1,8 @@
+package eznode
+
+// ResponseValidator checks the body of a response which has a successful status code
+// a response which fails validation is treated as a failure of the node, and the request is sent to the next node
+type ResponseValidator interface {
+	// Validate returns an error if the response must not be returned to the caller
+	Validate(response *Response) error
+}
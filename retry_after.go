@@ -0,0 +1,36 @@
+package eznode
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// parseRetryAfter returns how long the node asked to wait before the next request
+// Retry-After is either number of seconds or an HTTP-date
+func parseRetryAfter(res *Response, now time.Time) (time.Duration, bool) {
+	if res == nil || res.Headers == nil {
+		return 0, false
+	}
+
+	if res.StatusCode != http.StatusTooManyRequests && res.StatusCode != http.StatusServiceUnavailable {
+		return 0, false
+	}
+
+	retryAfter := strings.TrimSpace(res.Headers.Get("Retry-After"))
+	if retryAfter == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.ParseUint(retryAfter, 10, 32); err == nil {
+		return time.Duration(seconds) * time.Second, seconds > 0
+	}
+
+	retryTime, err := http.ParseTime(retryAfter)
+	if err != nil || !retryTime.After(now) {
+		return 0, false
+	}
+
+	return retryTime.Sub(now), true
+}
@@ -0,0 +1,107 @@
+package eznode
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	res := &Response{
+		StatusCode: http.StatusTooManyRequests,
+		Headers:    &http.Header{"Retry-After": []string{"120"}},
+	}
+	retryAfter, ok := parseRetryAfter(res, now)
+	assert.True(t, ok)
+	assert.Equal(t, 120*time.Second, retryAfter)
+
+	res.Headers.Set("Retry-After", now.Add(1*time.Hour).UTC().Format(http.TimeFormat))
+	retryAfter, ok = parseRetryAfter(res, now)
+	assert.True(t, ok)
+	assert.InDelta(t, float64(1*time.Hour), float64(retryAfter), float64(time.Second))
+
+	res.StatusCode = http.StatusOK
+	_, ok = parseRetryAfter(res, now)
+	assert.False(t, ok, "should only honor Retry-After of 429 and 503")
+}
+
+func TestThrottleNodeOnRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	mockedApiCall := mockApiCall{
+		returnFunc: func(request *http.Request) (*Response, error) {
+			if request.URL.Host == "example.com" {
+				return &Response{
+					StatusCode: http.StatusTooManyRequests,
+					Headers:    &http.Header{"Retry-After": []string{"30"}},
+				}, nil
+			}
+
+			return &Response{
+				StatusCode: http.StatusOK,
+				Headers:    &http.Header{},
+			}, nil
+		},
+		validateFunc: func(request *http.Request) {
+		},
+	}
+
+	chainNode1 := NewChainNode(NewChainNodeConfig{
+		Name: "Node 1",
+		Url:  "http://example.com",
+		Limit: ChainNodeLimit{
+			Count: 10,
+			Per:   1 * time.Second,
+		},
+		RequestTimeout: 1 * time.Second,
+		Priority:       2,
+	})
+
+	chainNode2 := NewChainNode(NewChainNodeConfig{
+		Name: "Node 2",
+		Url:  "http://example2.com",
+		Limit: ChainNodeLimit{
+			Count: 10,
+			Per:   1 * time.Second,
+		},
+		RequestTimeout: 1 * time.Second,
+		Priority:       1,
+	})
+
+	createdChain := NewChain(
+		NewChainConfig{
+			Id: "test-chain",
+			Nodes: []*ChainNode{
+				chainNode1,
+				chainNode2,
+			},
+			CheckTickRate: CheckTick{
+				TickRate:         100 * time.Millisecond,
+				MaxCheckDuration: 200 * time.Millisecond,
+			},
+			RetryCount:    2,
+			MaxRetryAfter: 10 * time.Second,
+		},
+	)
+
+	ezNode := NewEzNode([]*Chain{createdChain}, WithApiClient(mockedApiCall))
+	request, _ := http.NewRequest("GET", "/", nil)
+
+	res, err := ezNode.SendRequest(context.Background(), "test-chain", request)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(res.Metadata.Trace))
+
+	res, err = ezNode.SendRequest(context.Background(), "test-chain", request)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(res.Metadata.Trace), "should not select throttled node")
+	assert.Equal(t, chainNode2.name, res.Metadata.Trace[0].NodeName)
+
+	throttledUntil := ezNode.GetStats()[0].Nodes[0].ThrottledUntil
+	assert.WithinDuration(t, time.Now().Add(10*time.Second), throttledUntil, time.Second)
+}
@@ -0,0 +1,60 @@
+package eznode
+
+import (
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// JitterMode determines how randomness is added to retry delays
+type JitterMode int
+
+const (
+	// NoJitter waits exactly the computed delay
+	NoJitter JitterMode = iota
+	// FullJitter waits a random duration between zero and the computed delay
+	FullJitter
+	// EqualJitter waits half of the computed delay plus a random duration up to the other half
+	EqualJitter
+)
+
+// RetryPolicy determines how long to wait between attempts of a request (exponential backoff)
+type RetryPolicy struct {
+	// InitialDelay is the delay before the first retry
+	InitialDelay time.Duration
+	// Multiplier grows the delay of every next retry, 2 is used by default
+	Multiplier float64
+	// MaxDelay caps the delay
+	// MaxDelay is optional
+	MaxDelay time.Duration
+	// Jitter spreads retries of concurrent requests, NoJitter is used by default
+	Jitter JitterMode
+}
+
+// delay returns how long to wait before the given retry, retry starts from 1
+func (p *RetryPolicy) delay(retry int) time.Duration {
+	if retry < 1 || p.InitialDelay <= 0 {
+		return 0
+	}
+
+	multiplier := p.Multiplier
+	if multiplier == 0 {
+		multiplier = 2
+	}
+
+	delay := float64(p.InitialDelay) * math.Pow(multiplier, float64(retry-1))
+	if p.MaxDelay > 0 && delay > float64(p.MaxDelay) {
+		delay = float64(p.MaxDelay)
+	}
+	// avoid overflow of time.Duration
+	delay = math.Min(delay, math.MaxInt64)
+
+	switch p.Jitter {
+	case FullJitter:
+		return time.Duration(rand.Float64() * delay)
+	case EqualJitter:
+		return time.Duration(delay/2 + rand.Float64()*delay/2)
+	default:
+		return time.Duration(delay)
+	}
+}
@@ -0,0 +1,121 @@
+package eznode
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicyDelay(t *testing.T) {
+	t.Parallel()
+
+	policy := &RetryPolicy{
+		InitialDelay: 100 * time.Millisecond,
+		Multiplier:   3,
+		MaxDelay:     500 * time.Millisecond,
+	}
+	assert.Equal(t, time.Duration(0), policy.delay(0))
+	assert.Equal(t, 100*time.Millisecond, policy.delay(1))
+	assert.Equal(t, 300*time.Millisecond, policy.delay(2))
+	assert.Equal(t, 500*time.Millisecond, policy.delay(3))
+
+	policy.Jitter = FullJitter
+	for i := 0; i < 10; i++ {
+		assert.Less(t, policy.delay(2), 300*time.Millisecond)
+	}
+
+	policy.Jitter = EqualJitter
+	for i := 0; i < 10; i++ {
+		delay := policy.delay(2)
+		assert.GreaterOrEqual(t, delay, 150*time.Millisecond)
+		assert.Less(t, delay, 300*time.Millisecond)
+	}
+}
+
+func TestRetryPolicyWaitsBetweenAttempts(t *testing.T) {
+	t.Parallel()
+
+	mockedApiCall := mockApiCall{
+		returnFunc: func(request *http.Request) (*Response, error) {
+			if request.URL.Host == "example3.com" {
+				return &Response{
+					StatusCode: http.StatusOK,
+					Headers:    &http.Header{},
+				}, nil
+			}
+
+			return nil, errors.New("error")
+		},
+		validateFunc: func(request *http.Request) {
+		},
+	}
+
+	chainNode1 := NewChainNode(NewChainNodeConfig{
+		Name: "Node 1",
+		Url:  "http://example.com",
+		Limit: ChainNodeLimit{
+			Count: 10,
+			Per:   2 * time.Second,
+		},
+		RequestTimeout: 1 * time.Second,
+		Priority:       3,
+	})
+
+	chainNode2 := NewChainNode(NewChainNodeConfig{
+		Name: "Node 2",
+		Url:  "http://example2.com",
+		Limit: ChainNodeLimit{
+			Count: 10,
+			Per:   2 * time.Second,
+		},
+		RequestTimeout: 1 * time.Second,
+		Priority:       2,
+	})
+
+	chainNode3 := NewChainNode(NewChainNodeConfig{
+		Name: "Node 3",
+		Url:  "http://example3.com",
+		Limit: ChainNodeLimit{
+			Count: 10,
+			Per:   2 * time.Second,
+		},
+		RequestTimeout: 1 * time.Second,
+		Priority:       1,
+	})
+
+	createdChain := NewChain(
+		NewChainConfig{
+			Id: "test-chain",
+			Nodes: []*ChainNode{
+				chainNode1,
+				chainNode2,
+				chainNode3,
+			},
+			CheckTickRate: CheckTick{
+				TickRate:         100 * time.Millisecond,
+				MaxCheckDuration: 200 * time.Millisecond,
+			},
+			RetryCount: 2,
+			RetryPolicy: &RetryPolicy{
+				InitialDelay: 50 * time.Millisecond,
+			},
+		},
+	)
+
+	ezNode := NewEzNode([]*Chain{createdChain}, WithApiClient(mockedApiCall))
+	request, _ := http.NewRequest("GET", "/", nil)
+
+	startTime := time.Now()
+	res, err := ezNode.SendRequest(context.Background(), "test-chain", request)
+	assert.Nil(t, err)
+	assert.GreaterOrEqual(t, time.Since(startTime), 150*time.Millisecond)
+	assert.Equal(t, 2, res.Metadata.Retry)
+	assert.Equal(t, 3, len(res.Metadata.Trace))
+	assert.Equal(t, time.Duration(0), res.Metadata.Trace[0].Wait)
+	assert.Equal(t, 50*time.Millisecond, res.Metadata.Trace[1].Wait)
+	assert.Equal(t, 100*time.Millisecond, res.Metadata.Trace[2].Wait)
+}
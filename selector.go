@@ -0,0 +1,30 @@
+package eznode
+
+// Selector picks the node which serves a request
+// Select receives free nodes of the highest available priority and is called while the chain is locked,
+// so it must not call back into the chain. Returning nil means no node is selected.
+type Selector interface {
+	Select(nodes []*ChainNode) *ChainNode
+}
+
+// LeastHitsSelector selects the node which has the fewest hits, the node limit has not recovered from
+type LeastHitsSelector struct{}
+
+// NewLeastHitsSelector creates a new LeastHitsSelector
+func NewLeastHitsSelector() *LeastHitsSelector {
+	return &LeastHitsSelector{}
+}
+
+func (s *LeastHitsSelector) Select(nodes []*ChainNode) *ChainNode {
+	var selectedNode *ChainNode
+	var selectedHits uint
+	for _, node := range nodes {
+		hits := node.hits()
+		if selectedNode == nil || hits < selectedHits {
+			selectedNode = node
+			selectedHits = hits
+		}
+	}
+
+	return selectedNode
+}
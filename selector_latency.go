@@ -0,0 +1,53 @@
+package eznode
+
+import "time"
+
+// LatencySelector selects the node which has the lowest average response time among healthy nodes
+// a node is healthy when its error rate is not greater than MaxErrorRate
+// nodes which have not responded yet are selected first, so their latency gets measured
+type LatencySelector struct {
+	maxErrorRate float64
+}
+
+// NewLatencySelector creates a new LatencySelector
+// maxErrorRate is between 0 and 1, if no node is healthy the node with the lowest error rate is selected
+func NewLatencySelector(maxErrorRate float64) *LatencySelector {
+	return &LatencySelector{
+		maxErrorRate: maxErrorRate,
+	}
+}
+
+func (s *LatencySelector) Select(nodes []*ChainNode) *ChainNode {
+	var fastestNode *ChainNode
+	var fastestLatency time.Duration
+	var healthiestNode *ChainNode
+	var lowestErrorRate float64
+	for _, node := range nodes {
+		latency := node.Latency()
+		errorRate := node.ErrorRate()
+
+		if healthiestNode == nil || errorRate < lowestErrorRate {
+			healthiestNode = node
+			lowestErrorRate = errorRate
+		}
+
+		if errorRate > s.maxErrorRate {
+			continue
+		}
+
+		if latency == 0 {
+			return node
+		}
+
+		if fastestNode == nil || latency < fastestLatency {
+			fastestNode = node
+			fastestLatency = latency
+		}
+	}
+
+	if fastestNode != nil {
+		return fastestNode
+	}
+
+	return healthiestNode
+}
@@ -0,0 +1,31 @@
+package eznode
+
+// LeastConnectionsSelector selects the node which has the fewest in-flight requests
+// ties are broken by the fewest hits, the node limit has not recovered from
+type LeastConnectionsSelector struct{}
+
+// NewLeastConnectionsSelector creates a new LeastConnectionsSelector
+func NewLeastConnectionsSelector() *LeastConnectionsSelector {
+	return &LeastConnectionsSelector{}
+}
+
+func (s *LeastConnectionsSelector) Select(nodes []*ChainNode) *ChainNode {
+	var selectedNode *ChainNode
+	for _, node := range nodes {
+		if selectedNode == nil || lessLoaded(node, selectedNode) {
+			selectedNode = node
+		}
+	}
+
+	return selectedNode
+}
+
+func lessLoaded(node *ChainNode, other *ChainNode) bool {
+	nodeInFlight := node.InFlight()
+	otherInFlight := other.InFlight()
+	if nodeInFlight != otherInFlight {
+		return nodeInFlight < otherInFlight
+	}
+
+	return node.hits() < other.hits()
+}
@@ -0,0 +1,33 @@
+package eznode
+
+import "math/rand/v2"
+
+// PowerOfTwoChoicesSelector picks two random nodes and selects the one which has fewer in-flight requests
+type PowerOfTwoChoicesSelector struct{}
+
+// NewPowerOfTwoChoicesSelector creates a new PowerOfTwoChoicesSelector
+func NewPowerOfTwoChoicesSelector() *PowerOfTwoChoicesSelector {
+	return &PowerOfTwoChoicesSelector{}
+}
+
+func (s *PowerOfTwoChoicesSelector) Select(nodes []*ChainNode) *ChainNode {
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	if len(nodes) == 1 {
+		return nodes[0]
+	}
+
+	first := rand.IntN(len(nodes))
+	second := rand.IntN(len(nodes) - 1)
+	if second >= first {
+		second += 1
+	}
+
+	if lessLoaded(nodes[second], nodes[first]) {
+		return nodes[second]
+	}
+
+	return nodes[first]
+}
@@ -0,0 +1,19 @@
+package eznode
+
+import "math/rand/v2"
+
+// RandomSelector selects a random node
+type RandomSelector struct{}
+
+// NewRandomSelector creates a new RandomSelector
+func NewRandomSelector() *RandomSelector {
+	return &RandomSelector{}
+}
+
+func (s *RandomSelector) Select(nodes []*ChainNode) *ChainNode {
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	return nodes[rand.IntN(len(nodes))]
+}
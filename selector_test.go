@@ -0,0 +1,229 @@
+package eznode
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWeightedRoundRobinSelector(t *testing.T) {
+	t.Parallel()
+
+	chainNode1 := NewChainNode(NewChainNodeConfig{
+		Name: "Node 1",
+		Url:  "http://example.com",
+		Limit: ChainNodeLimit{
+			Count: 100,
+			Per:   10 * time.Second,
+		},
+		RequestTimeout: 1 * time.Second,
+		Priority:       1,
+		Weight:         3,
+	})
+
+	chainNode2 := NewChainNode(NewChainNodeConfig{
+		Name: "Node 2",
+		Url:  "http://example.com",
+		Limit: ChainNodeLimit{
+			Count: 100,
+			Per:   10 * time.Second,
+		},
+		RequestTimeout: 1 * time.Second,
+		Priority:       1,
+		Weight:         1,
+	})
+
+	createdChain := NewChain(
+		NewChainConfig{
+			Id: "test chain",
+			Nodes: []*ChainNode{
+				chainNode1,
+				chainNode2,
+			},
+			CheckTickRate: CheckTick{
+				TickRate:         100 * time.Millisecond,
+				MaxCheckDuration: 1 * time.Second,
+			},
+			RetryCount: 2,
+			Selector:   NewWeightedRoundRobinSelector(),
+		},
+	)
+
+	selected := make(map[string]int)
+	for i := 0; i < 8; i++ {
+		foundNode, _ := createdChain.getFreeNode(context.Background(), nodeQuery{})
+		selected[foundNode.name] += 1
+	}
+
+	assert.Equal(t, 6, selected[chainNode1.name])
+	assert.Equal(t, 2, selected[chainNode2.name])
+}
+
+func TestLeastConnectionsSelector(t *testing.T) {
+	t.Parallel()
+
+	chainNode1 := NewChainNode(NewChainNodeConfig{
+		Name: "Node 1",
+		Url:  "http://example.com",
+		Limit: ChainNodeLimit{
+			Count: 10,
+			Per:   10 * time.Second,
+		},
+		RequestTimeout: 1 * time.Second,
+		Priority:       1,
+	})
+
+	chainNode2 := NewChainNode(NewChainNodeConfig{
+		Name: "Node 2",
+		Url:  "http://example.com",
+		Limit: ChainNodeLimit{
+			Count: 10,
+			Per:   10 * time.Second,
+		},
+		RequestTimeout: 1 * time.Second,
+		Priority:       1,
+	})
+
+	createdChain := NewChain(
+		NewChainConfig{
+			Id: "test chain",
+			Nodes: []*ChainNode{
+				chainNode1,
+				chainNode2,
+			},
+			CheckTickRate: CheckTick{
+				TickRate:         100 * time.Millisecond,
+				MaxCheckDuration: 1 * time.Second,
+			},
+			RetryCount: 2,
+			Selector:   NewLeastConnectionsSelector(),
+		},
+	)
+
+	chainNode1.inFlight = 2
+	chainNode2.inFlight = 1
+	chainNode2.limiters[0].take(time.Now(), 5)
+
+	foundNode, _ := createdChain.getFreeNode(context.Background(), nodeQuery{})
+	assert.Equal(t, chainNode2.name, foundNode.name, "should route to node with fewer in-flight requests")
+	foundNode, _ = createdChain.getFreeNode(context.Background(), nodeQuery{})
+	assert.Equal(t, chainNode1.name, foundNode.name, "should break tie by hits")
+}
+
+func TestSelectorRespectsPriority(t *testing.T) {
+	t.Parallel()
+
+	chainNode1 := NewChainNode(NewChainNodeConfig{
+		Name: "Node 1",
+		Url:  "http://example.com",
+		Limit: ChainNodeLimit{
+			Count: 10,
+			Per:   10 * time.Second,
+		},
+		RequestTimeout: 1 * time.Second,
+		Priority:       1,
+	})
+
+	chainNode2 := NewChainNode(NewChainNodeConfig{
+		Name: "Node 2",
+		Url:  "http://example.com",
+		Limit: ChainNodeLimit{
+			Count: 2,
+			Per:   10 * time.Second,
+		},
+		RequestTimeout: 1 * time.Second,
+		Priority:       2,
+	})
+
+	createdChain := NewChain(
+		NewChainConfig{
+			Id: "test chain",
+			Nodes: []*ChainNode{
+				chainNode1,
+				chainNode2,
+			},
+			CheckTickRate: CheckTick{
+				TickRate:         100 * time.Millisecond,
+				MaxCheckDuration: 1 * time.Second,
+			},
+			RetryCount: 2,
+			Selector:   NewRandomSelector(),
+		},
+	)
+
+	foundNode, _ := createdChain.getFreeNode(context.Background(), nodeQuery{})
+	assert.Equal(t, chainNode2.name, foundNode.name)
+	foundNode, _ = createdChain.getFreeNode(context.Background(), nodeQuery{})
+	assert.Equal(t, chainNode2.name, foundNode.name)
+	foundNode, _ = createdChain.getFreeNode(context.Background(), nodeQuery{})
+	assert.Equal(t, chainNode1.name, foundNode.name, "should fall back to lower priority when higher is full")
+}
+
+func TestLatencySelector(t *testing.T) {
+	t.Parallel()
+
+	chainNode1 := NewChainNode(NewChainNodeConfig{
+		Name: "Node 1",
+		Url:  "http://example.com",
+		Limit: ChainNodeLimit{
+			Count: 10,
+			Per:   10 * time.Second,
+		},
+		RequestTimeout: 1 * time.Second,
+		Priority:       1,
+	})
+
+	chainNode2 := NewChainNode(NewChainNodeConfig{
+		Name: "Node 2",
+		Url:  "http://example.com",
+		Limit: ChainNodeLimit{
+			Count: 10,
+			Per:   10 * time.Second,
+		},
+		RequestTimeout: 1 * time.Second,
+		Priority:       1,
+	})
+
+	chainNode3 := NewChainNode(NewChainNodeConfig{
+		Name: "Node 3",
+		Url:  "http://example.com",
+		Limit: ChainNodeLimit{
+			Count: 10,
+			Per:   10 * time.Second,
+		},
+		RequestTimeout: 1 * time.Second,
+		Priority:       1,
+	})
+
+	createdChain := NewChain(
+		NewChainConfig{
+			Id: "test chain",
+			Nodes: []*ChainNode{
+				chainNode1,
+				chainNode2,
+				chainNode3,
+			},
+			CheckTickRate: CheckTick{
+				TickRate:         100 * time.Millisecond,
+				MaxCheckDuration: 1 * time.Second,
+			},
+			RetryCount: 2,
+			Selector:   NewLatencySelector(0.5),
+		},
+	)
+
+	okResponse := &Response{StatusCode: 200}
+	collectMetric(chainNode1, okResponse, nil, true, 400*time.Millisecond)
+	collectMetric(chainNode2, okResponse, nil, true, 50*time.Millisecond)
+	collectMetric(chainNode3, okResponse, nil, true, 10*time.Millisecond)
+	for i := 0; i < 5; i++ {
+		collectMetric(chainNode3, okResponse, nil, false, 10*time.Millisecond)
+	}
+
+	foundNode, _ := createdChain.getFreeNode(context.Background(), nodeQuery{})
+	assert.Equal(t, chainNode2.name, foundNode.name, "should route to fastest healthy node")
+	assert.Equal(t, 50*time.Millisecond, chainNode2.Latency())
+	assert.Greater(t, chainNode3.ErrorRate(), 0.5)
+}
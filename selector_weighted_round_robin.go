@@ -0,0 +1,40 @@
+package eznode
+
+import "sync"
+
+// WeightedRoundRobinSelector distributes requests between nodes proportional to their weight
+// it uses smooth weighted round-robin, so heavy nodes are not picked in bursts
+type WeightedRoundRobinSelector struct {
+	mutex          *sync.Mutex
+	currentWeights map[*ChainNode]int64
+}
+
+// NewWeightedRoundRobinSelector creates a new WeightedRoundRobinSelector
+func NewWeightedRoundRobinSelector() *WeightedRoundRobinSelector {
+	return &WeightedRoundRobinSelector{
+		mutex:          &sync.Mutex{},
+		currentWeights: make(map[*ChainNode]int64),
+	}
+}
+
+func (s *WeightedRoundRobinSelector) Select(nodes []*ChainNode) *ChainNode {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var selectedNode *ChainNode
+	totalWeight := int64(0)
+	for _, node := range nodes {
+		s.currentWeights[node] += int64(node.weight)
+		totalWeight += int64(node.weight)
+
+		if selectedNode == nil || s.currentWeights[node] > s.currentWeights[selectedNode] {
+			selectedNode = node
+		}
+	}
+
+	if selectedNode != nil {
+		s.currentWeights[selectedNode] -= totalWeight
+	}
+
+	return selectedNode
+}
@@ -1,19 +1,33 @@
 package eznode
 
+import "time"
+
 // ChainNodeStats is the stats of a chain node
 type ChainNodeStats struct {
-	Name          string         `json:"name"`
-	CurrentHits   uint           `json:"current_hits"`
-	TotalHits     uint64         `json:"total_hits"`
-	Limits        uint           `json:"limits"`
-	ResponseStats map[int]uint64 `json:"response_stats"`
-	Priority      int            `json:"priority"`
-	Disabled      bool           `json:"disabled"`
-	Fails         uint           `json:"fails"`
+	Name           string                `json:"name"`
+	CurrentHits    uint                  `json:"current_hits"`
+	TotalHits      uint64                `json:"total_hits"`
+	Limits         uint                  `json:"limits"`
+	ResponseStats  map[int]uint64        `json:"response_stats"`
+	Priority       int                   `json:"priority"`
+	Disabled       bool                  `json:"disabled"`
+	Fails          uint                  `json:"fails"`
+	Latency        time.Duration         `json:"latency"`
+	ErrorRate      float64               `json:"error_rate"`
+	CircuitState   string                `json:"circuit_state"`
+	Healthy        bool                  `json:"healthy"`
+	Head           uint64                `json:"head"`
+	HeadLag        uint64                `json:"head_lag"`
+	LimitStats     []ChainNodeLimitStats `json:"limit_stats"`
+	ThrottledUntil time.Time             `json:"throttled_until"`
+	EffectiveLimit uint                  `json:"effective_limit"`
 }
 
 // ChainStats is the stats of a chain
 type ChainStats struct {
-	Id    string           `json:"id"`
-	Nodes []ChainNodeStats `json:"nodes"`
+	Id          string           `json:"id"`
+	Nodes       []ChainNodeStats `json:"nodes"`
+	QueueLength int              `json:"queue_length"`
+	CacheHits   uint64           `json:"cache_hits"`
+	CacheMisses uint64           `json:"cache_misses"`
 }
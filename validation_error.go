@@ -0,0 +1,66 @@
+package eznode
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FieldError is an invalid field of a config
+type FieldError struct {
+	// Field is the path of the field, e.g. limits[1].count
+	Field string
+	// Message tells why the field is invalid
+	Message string
+}
+
+func (e FieldError) Error() string {
+	return e.Field + " " + e.Message
+}
+
+// ValidationError lists every invalid field of a config
+// errors.As can be used to find a FieldError in it
+type ValidationError struct {
+	Errors []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, fieldError := range e.Errors {
+		messages = append(messages, fieldError.Error())
+	}
+
+	return "invalid config: " + strings.Join(messages, "; ")
+}
+
+func (e *ValidationError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, fieldError := range e.Errors {
+		errs = append(errs, fieldError)
+	}
+
+	return errs
+}
+
+// fieldErrors collects invalid fields while a config is validated
+type fieldErrors []FieldError
+
+func (f *fieldErrors) add(field string, message string) {
+	*f = append(*f, FieldError{
+		Field:   field,
+		Message: message,
+	})
+}
+
+func (f fieldErrors) err() error {
+	if len(f) == 0 {
+		return nil
+	}
+
+	return &ValidationError{
+		Errors: f,
+	}
+}
+
+func indexedField(field string, index int) string {
+	return fmt.Sprintf("%s[%d]", field, index)
+}
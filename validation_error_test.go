@@ -0,0 +1,106 @@
+package eznode
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewChainNodeE(t *testing.T) {
+	t.Parallel()
+
+	chainNode, err := NewChainNodeE(NewChainNodeConfig{
+		Url: "http://example.com",
+		Limit: ChainNodeLimit{
+			Count: 0,
+			Per:   time.Second,
+		},
+		Limits: []ChainNodeLimit{
+			{
+				Count: 10,
+				Per:   0,
+			},
+		},
+		RequestTimeout: time.Second,
+		Priority:       -1,
+	})
+	assert.Nil(t, chainNode)
+
+	var validationError *ValidationError
+	assert.True(t, errors.As(err, &validationError))
+	assert.Equal(t, []FieldError{
+		{Field: "name", Message: "cannot be empty"},
+		{Field: "limit.count", Message: "cannot be less than 1"},
+		{Field: "limits[0].per", Message: "cannot be less than 1"},
+		{Field: "priority", Message: "cannot be less than 0"},
+	}, validationError.Errors)
+
+	var fieldError FieldError
+	assert.True(t, errors.As(err, &fieldError))
+	assert.Equal(t, "name", fieldError.Field)
+
+	chainNode, err = NewChainNodeE(NewChainNodeConfig{
+		Name: "Node 1",
+		Url:  "http://example.com",
+		Limit: ChainNodeLimit{
+			Count: 10,
+			Per:   time.Second,
+		},
+		RequestTimeout: time.Second,
+	})
+	assert.Nil(t, err)
+	assert.NotNil(t, chainNode)
+}
+
+func TestNewChainE(t *testing.T) {
+	t.Parallel()
+
+	chainNode1 := NewChainNode(NewChainNodeConfig{
+		Name: "Node 1",
+		Url:  "http://example.com",
+		Limit: ChainNodeLimit{
+			Count: 10,
+			Per:   time.Second,
+		},
+		RequestTimeout: time.Second,
+	})
+
+	chain, err := NewChainE(NewChainConfig{
+		Id:    "test chain",
+		Nodes: []*ChainNode{chainNode1, chainNode1},
+		CheckTickRate: CheckTick{
+			TickRate:         10 * time.Millisecond,
+			MaxCheckDuration: time.Second,
+		},
+		Routes: []Route{
+			{
+				Methods: []string{"trace_block"},
+			},
+			{
+				Methods: []string{"debug_traceTransaction"},
+				Tags:    []string{"trace"},
+			},
+		},
+	})
+	assert.Nil(t, chain)
+	assert.EqualError(
+		t,
+		err,
+		"invalid config: checkTickRate.tickRate cannot be less than 50 millisecond; "+
+			"routes[0].tags cannot be empty; routes[1].tags no node has all of the tags; nodes[1].name cannot be duplicate",
+	)
+}
+
+func TestNewEzNodeE(t *testing.T) {
+	t.Parallel()
+
+	ezNode, err := NewEzNodeE([]*Chain{}, WithSyncInterval(0))
+	assert.Nil(t, ezNode)
+	assert.EqualError(t, err, "invalid config: syncInterval must be greater than 0")
+
+	ezNode, err = NewEzNodeE([]*Chain{}, WithSyncInterval(time.Minute))
+	assert.Nil(t, err)
+	assert.NotNil(t, ezNode)
+}